@@ -0,0 +1,61 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "sync/atomic"
+
+// adaptiveDecayShift sets how fast a learned size forgets:
+// every Release takes 1/16 off it before the build length is
+// considered, so a one-off outlier fades within a hundred or
+// so Releases of ordinary builds.
+const adaptiveDecayShift = 4
+
+// adaptiveSize is a decaying maximum of the build lengths
+// seen by Release.
+type adaptiveSize struct {
+	size int64 // accessed atomically
+}
+
+// WithAdaptiveSizing makes the pool learn the size of the
+// strings it builds. Release tracks a decaying maximum of
+// builder lengths, and builders allocated on a pool miss are
+// grown to it, or to the initial capacity if that is larger,
+// so that cold builders start roughly right-sized without
+// the caller guessing a capacity up front.
+//
+// The learned size never exceeds the pool's max retained
+// capacity (see WithMaxRetainedCap), since larger builders
+// would be dropped on Release anyway, and it decays with
+// every Release, so a single huge build does not inflate
+// every later allocation. The bookkeeping costs a few atomic
+// operations per Release, which is why it is opt-in.
+func WithAdaptiveSizing() Option {
+	return func(bp *StringPool) {
+		bp.adaptive = &adaptiveSize{}
+	}
+}
+
+// observe decays the learned size and raises it to n if n is
+// larger, capped to max unless max is zero.
+func (a *adaptiveSize) observe(n, max int) {
+	if max > 0 && n > max {
+		n = max
+	}
+	for {
+		old := atomic.LoadInt64(&a.size)
+		size := old - old>>adaptiveDecayShift
+		if int64(n) > size {
+			size = int64(n)
+		}
+		if size == old || atomic.CompareAndSwapInt64(&a.size, old, size) {
+			return
+		}
+	}
+}
+
+// learned returns the learned size.
+func (a *adaptiveSize) learned() int {
+	return int(atomic.LoadInt64(&a.size))
+}
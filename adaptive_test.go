@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAdaptiveSizeObserve(t *testing.T) {
+	tests := []struct {
+		name  string
+		max   int
+		sizes []int
+		want  int
+	}{
+		{"none", 0, nil, 0},
+		{"stable", 0, []int{100, 100, 100}, 100},
+		{"rising", 0, []int{10, 50, 100}, 100},
+		{"decays", 0, []int{160, 0}, 150},
+		{"outlier fades", 0, append([]int{1600}, slices.Repeat([]int{100}, 64)...), 100},
+		{"capped", 64, []int{1 << 20}, 64},
+		{"no cap", 0, []int{1 << 20}, 1 << 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &adaptiveSize{}
+			for _, n := range tt.sizes {
+				a.observe(n, tt.max)
+			}
+			if got := a.learned(); got != tt.want {
+				t.Errorf("learned() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithAdaptiveSizing(t *testing.T) {
+	const size = 300
+	payload := strings.Repeat("x", size)
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantMin int
+		wantMax int
+	}{
+		{"off", nil, 0, 0},
+		{"on", []Option{WithAdaptiveSizing()}, size, DefaultMaxRetainedCap},
+		{"initial cap larger", []Option{WithAdaptiveSizing(), WithInitialCap(4 * size)}, 4 * size, DefaultMaxRetainedCap},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(tt.opts...)
+			for i := 0; i < 16; i++ {
+				sb := p.Get()
+				sb.WriteString(payload)
+				p.Release(sb)
+			}
+
+			// builders that are held are never reused, so
+			// every Get after the first allocates cold
+			held := make([]*strings.Builder, 4)
+			for i := range held {
+				held[i] = p.Get()
+			}
+			for _, sb := range held[1:] {
+				if got := sb.Cap(); got < tt.wantMin || got > tt.wantMax {
+					t.Errorf("cold builder Cap() = %v, want in [%v, %v]", got, tt.wantMin, tt.wantMax)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "context"
+
+// BuildBatched reads lines from in and accumulates them,
+// each followed by a newline, in a pooled strings.Builder.
+// Every batchSize lines, flush is called with the batch and
+// the builder is reset for the next batch. A batchSize less
+// than one is treated as one.
+//
+// BuildBatched returns nil when in is closed, after flushing
+// any partial batch. If ctx is cancelled first, the partial
+// batch is flushed, so no line that was received is lost,
+// and ctx.Err() is returned.
+//
+// flush is called from the goroutine running BuildBatched
+// and must not retain the builder; the string it receives
+// remains valid after flush returns.
+func BuildBatched(ctx context.Context, in <-chan string, flush func(string), batchSize int) error {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	sb := Get()
+	defer Release(sb)
+
+	n := 0
+	emit := func() {
+		if n == 0 {
+			return
+		}
+		flush(sb.String())
+		sb.Reset()
+		n = 0
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			emit()
+			return ctx.Err()
+		case line, ok := <-in:
+			if !ok {
+				emit()
+				return nil
+			}
+			sb.WriteString(line)
+			sb.WriteByte('\n')
+			if n++; n == batchSize {
+				emit()
+			}
+		}
+	}
+}
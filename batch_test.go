@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuildBatched(t *testing.T) {
+	tests := []struct {
+		name      string
+		lines     []string
+		batchSize int
+		want      []string
+	}{
+		{"empty", nil, 2, nil},
+		{"exact batches", []string{"a", "b", "c", "d"}, 2, []string{"a\nb\n", "c\nd\n"}},
+		{"partial final batch", []string{"a", "b", "c"}, 2, []string{"a\nb\n", "c\n"}},
+		{"single batch", []string{"a", "b"}, 10, []string{"a\nb\n"}},
+		{"zero batch size", []string{"a", "b"}, 0, []string{"a\n", "b\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := make(chan string, len(tt.lines))
+			for _, line := range tt.lines {
+				in <- line
+			}
+			close(in)
+
+			var got []string
+			err := BuildBatched(context.Background(), in, func(s string) {
+				got = append(got, s)
+			}, tt.batchSize)
+			if err != nil {
+				t.Errorf("BuildBatched() error = %v, want nil", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildBatched() flushed %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildBatchedCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan string)
+
+	var got []string
+	done := make(chan error)
+	go func() {
+		done <- BuildBatched(ctx, in, func(s string) {
+			got = append(got, s)
+		}, 2)
+	}()
+
+	in <- "a"
+	in <- "b"
+	in <- "c"
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("BuildBatched() error = %v, want %v", err, context.Canceled)
+	}
+	if want := []string{"a\nb\n", "c\n"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildBatched() flushed %q, want %q", got, want)
+	}
+}
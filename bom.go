@@ -0,0 +1,84 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// Byte order marks written by BuildWithBOM.
+const (
+	BOMUTF8    = "\xef\xbb\xbf"
+	BOMUTF16LE = "\xff\xfe"
+	BOMUTF16BE = "\xfe\xff"
+)
+
+// BuildWithBOM calls fn to build text in a builder from the
+// global pool and returns it prefixed with the byte order
+// mark for encoding, ready to be written to a text file.
+// encoding is matched case-insensitively and is one of:
+//
+//	"utf-8"     UTF-8 with BOMUTF8
+//	"utf-16le"  UTF-16, little-endian, with BOMUTF16LE
+//	"utf-16be"  UTF-16, big-endian, with BOMUTF16BE
+//	"none"      UTF-8 without a BOM
+//
+// fn always writes UTF-8; for the UTF-16 encodings the text
+// is converted after the BOM, since a UTF-16 BOM in front of
+// UTF-8 text would make the file unreadable. Invalid UTF-8 is
+// converted as U+FFFD. An unknown encoding is misuse and is
+// handled according to SetStrictMode as "none".
+func BuildWithBOM(encoding string, fn func(sb *strings.Builder)) string {
+	body := Get()
+	defer Release(body)
+	fn(body)
+
+	var bigEndian bool
+	switch strings.ToLower(encoding) {
+	case "utf-8":
+		return BOMUTF8 + body.String()
+	case "utf-16le":
+	case "utf-16be":
+		bigEndian = true
+	case "none":
+		return body.String()
+	default:
+		misuse("BuildWithBOM: unknown encoding %q", encoding)
+		return body.String()
+	}
+
+	sb := Get()
+	defer Release(sb)
+
+	if bigEndian {
+		sb.WriteString(BOMUTF16BE)
+	} else {
+		sb.WriteString(BOMUTF16LE)
+	}
+	sb.Grow(2 * body.Len())
+	for _, r := range body.String() {
+		if r1, r2 := utf16.EncodeRune(r); r1 != unicode.ReplacementChar {
+			writeUTF16(sb, uint16(r1), bigEndian)
+			writeUTF16(sb, uint16(r2), bigEndian)
+			continue
+		}
+		writeUTF16(sb, uint16(r), bigEndian)
+	}
+	return sb.String()
+}
+
+// writeUTF16 writes the code unit u to sb in the given byte
+// order.
+func writeUTF16(sb *strings.Builder, u uint16, bigEndian bool) {
+	if bigEndian {
+		sb.WriteByte(byte(u >> 8))
+		sb.WriteByte(byte(u))
+		return
+	}
+	sb.WriteByte(byte(u))
+	sb.WriteByte(byte(u >> 8))
+}
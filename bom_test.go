@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildWithBOM(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding string
+		in       string
+		want     string
+	}{
+		{"utf-8", "utf-8", "hi", BOMUTF8 + "hi"},
+		{"utf-8 upper case", "UTF-8", "hi", BOMUTF8 + "hi"},
+		{"utf-8 empty", "utf-8", "", BOMUTF8},
+		{"utf-16le", "utf-16le", "hi", BOMUTF16LE + "h\x00i\x00"},
+		{"utf-16be", "utf-16be", "hi", BOMUTF16BE + "\x00h\x00i"},
+		{"utf-16le non-ascii", "utf-16le", "é", BOMUTF16LE + "\xe9\x00"},
+		{"utf-16be surrogate pair", "utf-16be", "😀", BOMUTF16BE + "\xd8\x3d\xde\x00"},
+		{"utf-16le surrogate pair", "utf-16le", "😀", BOMUTF16LE + "\x3d\xd8\x00\xde"},
+		{"utf-16be invalid utf-8", "utf-16be", "\xff", BOMUTF16BE + "\xff\xfd"},
+		{"utf-16le empty", "utf-16le", "", BOMUTF16LE},
+		{"none", "none", "hi", "hi"},
+		{"unknown", "latin-1", "hi", "hi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildWithBOM(tt.encoding, func(sb *strings.Builder) {
+				sb.WriteString(tt.in)
+			})
+			if got != tt.want {
+				t.Errorf("BuildWithBOM(%q) = %q, want %q", tt.encoding, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildWithBOMMisuse(t *testing.T) {
+	withStrictMode(true, func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("BuildWithBOM() with unknown encoding did not panic in strict mode")
+			}
+		}()
+		BuildWithBOM("ebcdic", func(sb *strings.Builder) {})
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// BoundedPool is a StringPool with a hard ceiling on the
+// number of builders checked out at once, so that a runaway
+// caller cannot make the pool allocate without limit. Get
+// blocks while the ceiling is reached, until a builder is
+// released or the context is done.
+//
+// A BoundedPool is safe for use by multiple goroutines
+// simultaneously.
+type BoundedPool struct {
+	pool   *StringPool
+	tokens chan struct{} // one per checked out builder; nil if unbounded
+
+	mu  sync.Mutex
+	out map[*strings.Builder]struct{} // builders holding a token
+}
+
+// NewBounded returns a BoundedPool that hands out at most max
+// builders at a time. A max of zero or less means no limit,
+// in which case Get never blocks. opts configure the
+// underlying StringPool as they do for New.
+func NewBounded(max int, opts ...Option) *BoundedPool {
+	p := &BoundedPool{pool: New(opts...)}
+	if max > 0 {
+		p.tokens = make(chan struct{}, max)
+		p.out = make(map[*strings.Builder]struct{}, max)
+	}
+	return p
+}
+
+// Get returns an empty builder, blocking until one may be
+// checked out without exceeding the pool's ceiling. If ctx
+// is done first, Get returns nil and ctx.Err().
+func (p *BoundedPool) Get(ctx context.Context) (*strings.Builder, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if p.tokens == nil {
+		return p.pool.Get(), nil
+	}
+	select {
+	case p.tokens <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	sb := p.pool.Get()
+	p.mu.Lock()
+	p.out[sb] = struct{}{}
+	p.mu.Unlock()
+	return sb, nil
+}
+
+// Release returns sb to the pool, which lets one blocked Get
+// proceed. Releasing a nil builder is a no-op. Releasing a
+// builder that is not checked out from p, e.g. a second
+// time, is misuse and is handled according to SetStrictMode
+// by ignoring it, so it cannot free a slot held by another
+// builder.
+func (p *BoundedPool) Release(sb *strings.Builder) {
+	if sb == nil {
+		return
+	}
+	if p.tokens != nil {
+		p.mu.Lock()
+		_, ok := p.out[sb]
+		delete(p.out, sb)
+		p.mu.Unlock()
+		if !ok {
+			misuse("BoundedPool.Release: builder %p is not checked out", sb)
+			return
+		}
+		<-p.tokens
+	}
+	p.pool.Release(sb)
+}
+
+// InUse returns the number of builders checked out of a
+// bounded pool. It is always zero for an unbounded pool.
+func (p *BoundedPool) InUse() int {
+	return len(p.tokens)
+}
+
+// Stats returns a snapshot of the underlying pool's counters.
+func (p *BoundedPool) Stats() Stats {
+	return p.pool.Stats()
+}
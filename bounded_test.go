@@ -0,0 +1,129 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBoundedPool(t *testing.T) {
+	p := NewBounded(1)
+
+	first, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := p.InUse(); got != 1 {
+		t.Errorf("InUse() = %d, want 1", got)
+	}
+
+	// a second Get times out while the first builder is out
+	timedOut := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		sb, err := p.Get(ctx)
+		if sb != nil {
+			p.Release(sb)
+		}
+		timedOut <- err
+	}()
+	if err := <-timedOut; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() while full error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// a third Get blocks until the first builder is released
+	got := make(chan *strings.Builder, 1)
+	go func() {
+		sb, err := p.Get(context.Background())
+		if err != nil {
+			t.Errorf("blocked Get() error = %v", err)
+		}
+		got <- sb
+	}()
+	select {
+	case <-got:
+		t.Fatal("Get() returned while the pool was full")
+	case <-time.After(20 * time.Millisecond):
+	}
+	p.Release(first)
+
+	select {
+	case sb := <-got:
+		p.Release(sb)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Get() still blocked after Release()")
+	}
+	if got := p.InUse(); got != 0 {
+		t.Errorf("InUse() after Release() = %d, want 0", got)
+	}
+	if s := p.Stats(); s.Gets != 2 || s.Releases != 2 {
+		t.Errorf("Stats() = %+v, want 2 Gets and 2 Releases", s)
+	}
+}
+
+func TestBoundedPoolCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, max := range []int{0, 1} {
+		if sb, err := NewBounded(max).Get(ctx); sb != nil || !errors.Is(err, context.Canceled) {
+			t.Errorf("NewBounded(%d).Get(cancelled) = %v, %v, want nil, %v", max, sb, err, context.Canceled)
+		}
+	}
+}
+
+func TestBoundedPoolUnbounded(t *testing.T) {
+	for _, max := range []int{0, -1} {
+		p := NewBounded(max)
+		sbs := make([]*strings.Builder, 100)
+		for i := range sbs {
+			sb, err := p.Get(context.Background())
+			if err != nil {
+				t.Fatalf("NewBounded(%d).Get() error = %v", max, err)
+			}
+			sbs[i] = sb
+		}
+		if got := p.InUse(); got != 0 {
+			t.Errorf("NewBounded(%d).InUse() = %d, want 0", max, got)
+		}
+		for _, sb := range sbs {
+			p.Release(sb)
+		}
+	}
+}
+
+func TestBoundedPoolForeignRelease(t *testing.T) {
+	p := NewBounded(1)
+	sb, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	// neither a stranger nor a second release frees the slot
+	// held by sb
+	p.Release(&strings.Builder{})
+	if got := p.InUse(); got != 1 {
+		t.Errorf("InUse() after foreign Release() = %d, want 1", got)
+	}
+	p.Release(sb)
+	p.Release(sb)
+	if got := p.InUse(); got != 0 {
+		t.Errorf("InUse() after double Release() = %d, want 0", got)
+	}
+
+	withStrictMode(true, func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("foreign Release() in strict mode did not panic")
+			}
+		}()
+		p.Release(&strings.Builder{})
+	})
+}
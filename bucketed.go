@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"sort"
+	"strings"
+)
+
+// BucketedPool keeps builders in separate pools by capacity
+// class, so that workloads mixing small and very large
+// strings, e.g. short log lines and occasional large reports,
+// do not share builders across sizes. Builders are handed
+// out grown to their class, so a caller asking for a small
+// builder never receives one sized for the largest builds.
+//
+// Release resets builders, which detaches their backing
+// arrays as with StringPool, so a parked builder does not
+// keep its class's memory alive; the classes decide how
+// large a builder is grown when it is handed out.
+//
+// A BucketedPool is safe for use by multiple goroutines
+// simultaneously.
+type BucketedPool struct {
+	classes []int // ascending
+	pools   []*StringPool
+}
+
+// NewBucketed returns a BucketedPool with the given capacity
+// classes, in bytes, in any order. Duplicate classes are
+// merged; classes of zero or less are misuse and are
+// handled according to SetStrictMode by ignoring them.
+func NewBucketed(sizeClasses ...int) *BucketedPool {
+	classes := make([]int, 0, len(sizeClasses))
+	for _, c := range sizeClasses {
+		if c <= 0 {
+			misuse("NewBucketed: size class must be > 0, got %d", c)
+			continue
+		}
+		classes = append(classes, c)
+	}
+	sort.Ints(classes)
+
+	p := &BucketedPool{}
+	for i, c := range classes {
+		if i > 0 && c == classes[i-1] {
+			continue
+		}
+		p.classes = append(p.classes, c)
+		p.pools = append(p.pools, New(WithInitialCap(c), WithMaxRetainedCap(0)))
+	}
+	return p
+}
+
+// Classes returns the pool's capacity classes in ascending
+// order.
+func (p *BucketedPool) Classes() []int {
+	return append([]int(nil), p.classes...)
+}
+
+// class returns the index of the smallest class of at least
+// n bytes, or -1 if n exceeds the largest class.
+func (p *BucketedPool) class(n int) int {
+	i := sort.SearchInts(p.classes, n)
+	if i == len(p.classes) {
+		return -1
+	}
+	return i
+}
+
+// Get returns an empty builder from the smallest class.
+func (p *BucketedPool) Get() *strings.Builder {
+	return p.GetWithCapacity(0)
+}
+
+// GetWithCapacity returns an empty builder from the smallest
+// class of at least n bytes, grown to that class. If n
+// exceeds the largest class, the builder is allocated outside
+// the pool and grown to n; Release will drop it.
+func (p *BucketedPool) GetWithCapacity(n int) *strings.Builder {
+	i := p.class(n)
+	if i < 0 {
+		sb := &strings.Builder{}
+		sb.Grow(n)
+		return sb
+	}
+	return p.pools[i].GetWithCapacity(p.classes[i])
+}
+
+// Release returns sb to the smallest class that holds its
+// capacity. A builder larger than the largest class is
+// dropped. Releasing a nil builder is a no-op.
+func (p *BucketedPool) Release(sb *strings.Builder) {
+	if sb == nil {
+		return
+	}
+	i := p.class(sb.Cap())
+	if i < 0 {
+		sb.Reset()
+		return
+	}
+	p.pools[i].Release(sb)
+}
+
+// Stats returns a snapshot of the counters of each class, in
+// the order of Classes.
+func (p *BucketedPool) Stats() []Stats {
+	stats := make([]Stats, len(p.pools))
+	for i, sp := range p.pools {
+		stats[i] = sp.Stats()
+	}
+	return stats
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var _ Pooler = (*BucketedPool)(nil)
+
+func TestNewBucketed(t *testing.T) {
+	tests := []struct {
+		name    string
+		classes []int
+		want    []int
+	}{
+		{"none", nil, nil},
+		{"sorted", []int{64, 1024}, []int{64, 1024}},
+		{"unsorted with duplicates", []int{1024, 64, 1024}, []int{64, 1024}},
+		{"invalid dropped", []int{0, 64, -1}, []int{64}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewBucketed(tt.classes...).Classes()
+			if len(got) == 0 {
+				got = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewBucketed(%v).Classes() = %v, want %v", tt.classes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBucketedGetWithCapacity(t *testing.T) {
+	p := NewBucketed(64, 1024, 256<<10)
+
+	tests := []struct {
+		name    string
+		n       int
+		wantCap int
+	}{
+		{"zero", 0, 64},
+		{"small", 50, 64},
+		{"exact class", 64, 64},
+		{"medium", 65, 1024},
+		{"large", 200 << 10, 256 << 10},
+		{"beyond largest", 300 << 10, 300 << 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := p.GetWithCapacity(tt.n)
+			defer p.Release(sb)
+
+			if sb.Len() != 0 {
+				t.Errorf("GetWithCapacity(%d).Len() = %d, want 0", tt.n, sb.Len())
+			}
+			// the allocator may round up to its own size classes
+			if sb.Cap() < tt.wantCap || sb.Cap() >= 2*tt.wantCap {
+				t.Errorf("GetWithCapacity(%d).Cap() = %d, want about %d", tt.n, sb.Cap(), tt.wantCap)
+			}
+		})
+	}
+}
+
+func TestBucketedNoGiantReuse(t *testing.T) {
+	p := NewBucketed(64, 256<<10)
+
+	report := p.GetWithCapacity(200 << 10)
+	report.WriteString(strings.Repeat("r", 200<<10))
+	p.Release(report)
+
+	line := p.GetWithCapacity(50)
+	defer p.Release(line)
+	if line.Cap() > 64 {
+		t.Errorf("GetWithCapacity(50).Cap() after releasing a 200 KiB builder = %d, want <= 64", line.Cap())
+	}
+
+	stats := p.Stats()
+	if stats[1].Releases != 1 || stats[0].Releases != 0 {
+		t.Errorf("Stats() = %+v, want the report released to the large class only", stats)
+	}
+}
+
+func TestBucketedDropsOversized(t *testing.T) {
+	p := NewBucketed(64)
+
+	sb := p.Get()
+	sb.Grow(1 << 20)
+	sb.WriteString("big")
+	p.Release(sb)
+
+	if sb.Len() != 0 {
+		t.Errorf("Release() left Len() = %d, want 0", sb.Len())
+	}
+	if got := p.Stats()[0].Releases; got != 0 {
+		t.Errorf("Stats()[0].Releases = %d, want 0 for a dropped builder", got)
+	}
+	p.Release(nil)
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// BuildScope shares a single pooled strings.Builder across
+// several builds, e.g. all of the strings produced while
+// handling one request. The builder is reset between builds
+// and released only once, when the scope is closed, which
+// minimizes Get/Release churn within the scope.
+//
+// A BuildScope is not safe for use by multiple goroutines
+// simultaneously.
+type BuildScope struct {
+	pool *StringPool
+	sb   *strings.Builder
+}
+
+// NewBuildScope returns a BuildScope that draws its builder
+// from the global pool.
+func NewBuildScope() *BuildScope {
+	return Default().NewBuildScope()
+}
+
+// NewBuildScope returns a BuildScope that draws its builder
+// from the pool.
+func (bp *StringPool) NewBuildScope() *BuildScope {
+	return &BuildScope{pool: bp}
+}
+
+// Build resets the scope's builder, calls fn to fill it and
+// returns the resulting string. The builder is obtained from
+// the pool on the first call to Build.
+//
+// The builder must not be retained by fn.
+func (s *BuildScope) Build(fn func(sb *strings.Builder)) string {
+	if s.sb == nil {
+		s.sb = s.pool.Get()
+	} else {
+		s.sb.Reset()
+	}
+	fn(s.sb)
+	return s.sb.String()
+}
+
+// Close releases the scope's builder back to the pool. It
+// is safe to call Close more than once. Strings returned by
+// Build remain valid after Close.
+func (s *BuildScope) Close() {
+	if s.sb == nil {
+		return
+	}
+	s.pool.Release(s.sb)
+	s.sb = nil
+}
+
+// Build gets a builder from the global pool, calls fn to
+// fill it and returns the resulting string. The builder is
+// released when Build returns, even if fn panics, so there
+// is no Release to forget:
+//
+//	s := stringpool.Build(func(sb *strings.Builder) {
+//		sb.WriteString("hello")
+//	})
+//
+// The returned string remains valid after the builder is
+// reused: Release resets the builder, which detaches it from
+// the bytes the string refers to rather than overwriting
+// them. fn must not retain sb.
+func Build(fn func(sb *strings.Builder)) string {
+	return Default().Build(fn)
+}
+
+// Build gets a builder from the pool, calls fn to fill it and
+// returns the resulting string. See the package level Build
+// for details.
+func (bp *StringPool) Build(fn func(sb *strings.Builder)) string {
+	sb := bp.Get()
+	defer bp.Release(sb)
+
+	fn(sb)
+	return sb.String()
+}
+
+// GetString is Build with an optional capacity hint: the
+// builder from the global pool is grown to capHint bytes
+// before write is called, so that a string of known size is
+// built with a single allocation. See StringPool.GetString.
+func GetString(write func(sb *strings.Builder), capHint ...int) string {
+	return Default().GetString(write, capHint...)
+}
+
+// GetString gets a builder from the pool, grows it to
+// capHint bytes if a hint is given, calls write to fill it
+// and returns the resulting string. Only the first hint is
+// used, and one of zero or less skips the Grow.
+//
+// As with Build, the builder is released even if write
+// panics, and the returned string owns its bytes: Release
+// detaches the builder from them, so no later use of the
+// builder can change the string. write must not retain sb.
+func (bp *StringPool) GetString(write func(sb *strings.Builder), capHint ...int) string {
+	n := 0
+	if len(capHint) > 0 {
+		n = capHint[0]
+	}
+	sb := bp.GetWithCapacity(n)
+	defer bp.Release(sb)
+
+	write(sb)
+	return sb.String()
+}
+
+// RecursiveBuild is Build, named for recursive serializers:
+// fn, or the functions it calls for each level of the
+// structure, should append to the builder it is given rather
+// than getting a new builder per node, so that one builder
+// is reused for the whole recursion.
+func RecursiveBuild(fn func(sb *strings.Builder)) string {
+	return Build(fn)
+}
+
+// BuildTruncated calls fn to build a string in a builder from
+// the global pool. If the result is longer than maxRunes
+// runes, it is cut on a rune boundary and ellipsis is
+// appended, such that the returned string, ellipsis
+// included, is exactly maxRunes runes long:
+//
+//	BuildTruncated(8, "...", fn) // "hello, world" becomes "hello..."
+//
+// If ellipsis alone is longer than maxRunes, the ellipsis is
+// itself truncated. A maxRunes less than one returns the
+// empty string.
+func BuildTruncated(maxRunes int, ellipsis string, fn func(sb *strings.Builder)) string {
+	if maxRunes < 1 {
+		return ""
+	}
+
+	sb := Get()
+	defer Release(sb)
+
+	fn(sb)
+	s := sb.String()
+
+	// fast path: each rune is at least one byte
+	if len(s) <= maxRunes || utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+
+	budget := maxRunes - utf8.RuneCountInString(ellipsis)
+	if budget < 0 {
+		return ellipsis[:runeOffset(ellipsis, maxRunes)]
+	}
+	return s[:runeOffset(s, budget)] + ellipsis
+}
+
+// runeOffset returns the byte offset of the n-th rune in s,
+// or len(s) if s has n runes or fewer.
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+// lazyString is the fmt.Stringer returned by Lazy.
+type lazyString struct {
+	once sync.Once
+	fn   func(sb *strings.Builder)
+	s    string
+}
+
+// Lazy returns a fmt.Stringer that defers building a string
+// until it is needed. The first call to String calls fn with
+// a builder from the global pool and caches the result;
+// later calls return the cached string without calling fn.
+//
+// Lazy suits log messages that are expensive to build but
+// only formatted at some log levels:
+//
+//	log.Debugf("state: %v", stringpool.Lazy(dumpState))
+//
+// The returned Stringer is safe for use by multiple
+// goroutines simultaneously.
+func Lazy(fn func(sb *strings.Builder)) fmt.Stringer {
+	return &lazyString{fn: fn}
+}
+
+// String builds the string on first use and returns it.
+func (l *lazyString) String() string {
+	l.once.Do(func() {
+		l.s = Build(l.fn)
+		l.fn = nil
+	})
+	return l.s
+}
+
+// BuildMaxLines builds a bounded preview in a builder from
+// the global pool. fn emits lines by calling appendLine;
+// each line is written followed by a newline until maxLines
+// lines have been written. Further lines are counted but not
+// written, and a final marker line reports how many were
+// omitted:
+//
+//	... (12 more lines)
+//
+// No marker is written if fn emits maxLines lines or fewer.
+// A maxLines less than one omits every line.
+func BuildMaxLines(maxLines int, fn func(appendLine func(string))) string {
+	sb := Get()
+	defer Release(sb)
+
+	written, omitted := 0, 0
+	fn(func(line string) {
+		if written >= maxLines {
+			omitted++
+			return
+		}
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+		written++
+	})
+
+	if omitted > 0 {
+		sb.WriteString("... (")
+		sb.WriteString(SmallIntString(omitted))
+		if omitted == 1 {
+			sb.WriteString(" more line)\n")
+		} else {
+			sb.WriteString(" more lines)\n")
+		}
+	}
+	return sb.String()
+}
+
+// BuildN calls fn to build a string in a builder from the
+// global pool and returns the string together with its
+// length in bytes, captured from the builder. It saves a
+// separate len call in hot paths that need both.
+func BuildN(fn func(sb *strings.Builder)) (string, int) {
+	sb := Get()
+	defer Release(sb)
+
+	fn(sb)
+	return sb.String(), sb.Len()
+}
+
+// BuildWithProgress calls fn to build a string in a builder
+// from the global pool, passing it a report func with which
+// fn surfaces how many bytes it has written so far, e.g.
+// sb.Len() after each record of a long serialization. Each
+// report is forwarded to progress, which may drive a
+// progress bar.
+//
+// Once fn returns, progress is called with the final length
+// unless fn already reported it, so the last total seen by
+// progress always matches the returned string.
+func BuildWithProgress(progress func(total int), fn func(sb *strings.Builder, report func(bytesWritten int))) string {
+	sb := Get()
+	defer Release(sb)
+
+	last := -1
+	fn(sb, func(n int) {
+		last = n
+		progress(n)
+	})
+	if n := sb.Len(); n != last {
+		progress(n)
+	}
+	return sb.String()
+}
+
+// BuildSeq concatenates the strings yielded by seq in a
+// builder from the global pool and returns the result. It
+// integrates the pool with range-over-func iterators, e.g.
+//
+//	s := stringpool.BuildSeq(maps.Keys(m))
+//
+// BuildSeq raised the minimum Go version of this module to
+// 1.23, the first release with the iter package.
+func BuildSeq(seq iter.Seq[string]) string {
+	sb := Get()
+	defer Release(sb)
+
+	for s := range seq {
+		sb.WriteString(s)
+	}
+	return sb.String()
+}
+
+// BuildOrError calls fn to build a string in a builder from
+// the global pool that has been grown to at least capHint
+// bytes. If fn returns an error, e.g. because a bounded
+// write overflowed, BuildOrError discards the partial
+// output and returns the empty string and that error;
+// otherwise it returns the built string. The builder is
+// released in both cases. A capHint of zero or less skips
+// the initial Grow.
+func BuildOrError(capHint int, fn func(sb *strings.Builder) error) (string, error) {
+	sb := GetWithCapacity(capHint)
+	defer Release(sb)
+
+	if err := fn(sb); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,541 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name string
+		pool *StringPool
+		in   string
+	}{
+		{"global empty", nil, ""},
+		{"global", nil, "hello"},
+		{"pool", New(), "héllo, 世界"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := func(sb *strings.Builder) { sb.WriteString(tt.in) }
+
+			var got string
+			if tt.pool == nil {
+				got = Build(fn)
+			} else {
+				got = tt.pool.Build(fn)
+			}
+			if got != tt.in {
+				t.Errorf("Build() = %q, want %q", got, tt.in)
+			}
+		})
+	}
+}
+
+func TestBuildConcurrent(t *testing.T) {
+	p := New()
+	const workers, rounds = 2, 500
+
+	results := make([][]string, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				results[w] = append(results[w], p.Build(func(sb *strings.Builder) {
+					sb.WriteString(strings.Repeat(SmallIntString(w), 32))
+				}))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// check only after all builders have been reset and reused
+	for w, rs := range results {
+		want := strings.Repeat(SmallIntString(w), 32)
+		for i, got := range rs {
+			if got != want {
+				t.Fatalf("worker %d round %d: Build() = %q, want %q", w, i, got, want)
+			}
+		}
+	}
+}
+
+func TestBuildPanic(t *testing.T) {
+	p := New()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Build() did not propagate the panic")
+			}
+		}()
+		p.Build(func(sb *strings.Builder) {
+			sb.WriteString("partial")
+			panic("boom")
+		})
+	}()
+
+	if got := p.Stats().Releases; got != 1 {
+		t.Errorf("Stats().Releases after panic = %d, want 1", got)
+	}
+}
+
+func TestGetString(t *testing.T) {
+	tests := []struct {
+		name    string
+		hint    []int
+		wantCap int // minimum capacity seen by write
+	}{
+		{"no hint", nil, 0},
+		{"zero hint", []int{0}, 0},
+		{"negative hint", []int{-1}, 0},
+		{"hint", []int{64}, 64},
+		{"extra hints ignored", []int{32, 4096}, 32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			got := p.GetString(func(sb *strings.Builder) {
+				if sb.Cap() < tt.wantCap {
+					t.Errorf("Cap() in write = %d, want >= %d", sb.Cap(), tt.wantCap)
+				}
+				sb.WriteString("hello")
+			}, tt.hint...)
+			if got != "hello" {
+				t.Errorf("GetString() = %q, want %q", got, "hello")
+			}
+
+			// a later build in a reused builder must not
+			// change the string
+			p.GetString(func(sb *strings.Builder) { sb.WriteString("HELLO") })
+			if got != "hello" {
+				t.Errorf("GetString() result changed to %q", got)
+			}
+			if s := p.Stats(); s.Gets != 2 || s.Releases != 2 {
+				t.Errorf("Stats() = %+v, want 2 Gets and 2 Releases", s)
+			}
+		})
+	}
+
+	if got := GetString(func(sb *strings.Builder) { sb.WriteString("global") }, 6); got != "global" {
+		t.Errorf("GetString() = %q, want %q", got, "global")
+	}
+}
+
+func TestGetStringPanic(t *testing.T) {
+	p := New()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("GetString() did not propagate the panic")
+			}
+		}()
+		p.GetString(func(sb *strings.Builder) {
+			sb.WriteString("partial")
+			panic("boom")
+		}, 16)
+	}()
+
+	if got := p.Stats().Releases; got != 1 {
+		t.Errorf("Stats().Releases after panic = %d, want 1", got)
+	}
+}
+
+// TestGetStringNoLeak checks that every builder GetString
+// takes is released; BenchmarkGetString measures the memory.
+func TestGetStringNoLeak(t *testing.T) {
+	const n = 1000
+	p := New()
+	for i := 0; i < n; i++ {
+		out = p.GetString(func(sb *strings.Builder) { sb.WriteString("request") }, 7)
+	}
+	if s := p.Stats(); s.Gets != n || s.Releases != n || s.Outstanding != 0 {
+		t.Errorf("Stats() = %+v, want %d Gets and Releases, none outstanding", s, n)
+	}
+}
+
+// BenchmarkGetString compares GetString with Build. Besides
+// the usual allocation figures it reads runtime.MemStats
+// before and after the loop, garbage collecting first, and
+// reports the heap still in use afterwards (live-B), which
+// stays flat if no builders leak. To check a million
+// iterations:
+//
+//	go test -run XXX -bench GetString -benchtime 1000000x
+//
+// GetString allocates only the result string, 1 allocs/op.
+func BenchmarkGetString(b *testing.B) {
+	write := func(sb *strings.Builder) { sb.WriteString("request id 1234567890") }
+
+	tests := []struct {
+		name  string
+		build func(p *StringPool) string
+	}{
+		{"GetString", func(p *StringPool) string { return p.GetString(write, 21) }},
+		{"Build", func(p *StringPool) string { return p.Build(write) }},
+	}
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			p := New()
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				out = tt.build(p)
+			}
+			b.StopTimer()
+
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+			b.ReportMetric(float64(int64(after.HeapAlloc)-int64(before.HeapAlloc)), "live-B")
+			if n := p.Outstanding(); n != 0 {
+				b.Fatalf("%d builders outstanding after %d iterations", n, b.N)
+			}
+		})
+	}
+}
+
+func TestBuildScope(t *testing.T) {
+	scope := New().NewBuildScope()
+	defer scope.Close()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"first", "one", "one"},
+		{"second", "two", "two"},
+		{"empty", "", ""},
+		{"third", "three", "three"},
+	}
+
+	var first *strings.Builder
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var used *strings.Builder
+			got := scope.Build(func(sb *strings.Builder) {
+				used = sb
+				sb.WriteString(tt.in)
+			})
+			if got != tt.want {
+				t.Errorf("BuildScope.Build() = %q, want %q", got, tt.want)
+			}
+			if first == nil {
+				first = used
+			}
+			if used != first {
+				t.Errorf("BuildScope.Build() used builder %p, want %p", used, first)
+			}
+		})
+	}
+}
+
+func TestBuildScopeClose(t *testing.T) {
+	scope := NewBuildScope()
+	s := scope.Build(func(sb *strings.Builder) { sb.WriteString("kept") })
+	scope.Close()
+	scope.Close()
+
+	if s != "kept" {
+		t.Errorf("string after Close = %q, want %q", s, "kept")
+	}
+	if scope.sb != nil {
+		t.Errorf("BuildScope.Close() did not release the builder")
+	}
+}
+
+// node is a simple tree used to exercise recursive builds.
+type node struct {
+	name     string
+	children []node
+}
+
+func (n node) writeTo(sb *strings.Builder) {
+	sb.WriteString(n.name)
+	if len(n.children) == 0 {
+		return
+	}
+	sb.WriteByte('(')
+	for i, c := range n.children {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		c.writeTo(sb)
+	}
+	sb.WriteByte(')')
+}
+
+func TestRecursiveBuild(t *testing.T) {
+	tree := node{"root", []node{
+		{"a", []node{{"a1", nil}, {"a2", nil}}},
+		{"b", nil},
+		{"c", []node{{"c1", []node{{"c1x", nil}}}}},
+	}}
+	want := "root(a(a1 a2) b c(c1(c1x)))"
+
+	before := global.Stats()
+	got := RecursiveBuild(tree.writeTo)
+	after := global.Stats()
+
+	if got != want {
+		t.Errorf("RecursiveBuild() = %q, want %q", got, want)
+	}
+	if n := after.Gets - before.Gets; n != 1 {
+		t.Errorf("RecursiveBuild() made %d Gets, want 1", n)
+	}
+	if n := after.Releases - before.Releases; n != 1 {
+		t.Errorf("RecursiveBuild() made %d Releases, want 1", n)
+	}
+}
+
+func TestBuildTruncated(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxRunes int
+		ellipsis string
+		in       string
+		want     string
+	}{
+		{"under limit", 10, "...", "hello", "hello"},
+		{"at limit", 5, "...", "hello", "hello"},
+		{"over limit", 8, "...", "hello, world", "hello..."},
+		{"multibyte under", 5, "…", "héllo", "héllo"},
+		{"multibyte at limit", 4, "…", "日本語字", "日本語字"},
+		{"multibyte over", 4, "…", "日本語の文字", "日本語…"},
+		{"multibyte ellipsis", 6, "……", "αβγδεζηθ", "αβγδ……"},
+		{"empty ellipsis", 3, "", "abcdef", "abc"},
+		{"ellipsis too long", 2, "...", "abcdef", ".."},
+		{"ellipsis fills budget", 3, "...", "abcdef", "..."},
+		{"zero", 0, "...", "abc", ""},
+		{"empty", 3, "...", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildTruncated(tt.maxRunes, tt.ellipsis, func(sb *strings.Builder) {
+				sb.WriteString(tt.in)
+			})
+			if got != tt.want {
+				t.Errorf("BuildTruncated() = %q, want %q", got, tt.want)
+			}
+			if n := utf8.RuneCountInString(got); n > tt.maxRunes && tt.maxRunes >= 0 {
+				t.Errorf("BuildTruncated() returned %d runes, want <= %d", n, tt.maxRunes)
+			}
+		})
+	}
+}
+
+func TestLazy(t *testing.T) {
+	calls := 0
+	s := Lazy(func(sb *strings.Builder) {
+		calls++
+		sb.WriteString("expensive")
+	})
+
+	if calls != 0 {
+		t.Fatalf("Lazy() called fn %d times before String(), want 0", calls)
+	}
+	for i := 0; i < 3; i++ {
+		if got := s.String(); got != "expensive" {
+			t.Errorf("Lazy().String() = %q, want %q", got, "expensive")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Lazy() called fn %d times, want 1", calls)
+	}
+
+	if got := fmt.Sprintf("[%v]", s); got != "[expensive]" {
+		t.Errorf("fmt.Sprintf() = %q, want %q", got, "[expensive]")
+	}
+}
+
+func TestLazyUnused(t *testing.T) {
+	called := false
+	_ = Lazy(func(sb *strings.Builder) { called = true })
+	if called {
+		t.Errorf("Lazy() called fn without String()")
+	}
+}
+
+func TestBuildMaxLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxLines int
+		lines    int
+		want     string
+	}{
+		{"none", 3, 0, ""},
+		{"fewer", 3, 2, "line 0\nline 1\n"},
+		{"exactly", 3, 3, "line 0\nline 1\nline 2\n"},
+		{"one more", 3, 4, "line 0\nline 1\nline 2\n... (1 more line)\n"},
+		{"many more", 2, 300, "line 0\nline 1\n... (298 more lines)\n"},
+		{"zero max", 0, 2, "... (2 more lines)\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildMaxLines(tt.maxLines, func(appendLine func(string)) {
+				for i := 0; i < tt.lines; i++ {
+					appendLine("line " + SmallIntString(i))
+				}
+			})
+			if got != tt.want {
+				t.Errorf("BuildMaxLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildN(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"empty", ""},
+		{"ascii", "hello"},
+		{"multibyte", "héllo, 世界"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, n := BuildN(func(sb *strings.Builder) { sb.WriteString(tt.in) })
+			if s != tt.in {
+				t.Errorf("BuildN() string = %q, want %q", s, tt.in)
+			}
+			if n != len(s) {
+				t.Errorf("BuildN() length = %v, want %v", n, len(s))
+			}
+		})
+	}
+}
+
+func TestBuildWithProgress(t *testing.T) {
+	tests := []struct {
+		name      string
+		records   []string
+		reportEnd bool
+		want      []int
+	}{
+		{"none", nil, false, []int{0}},
+		{"each record", []string{"ab", "cde", "f"}, false, []int{3, 7, 9}},
+		{"final reported by fn", []string{"ab", "cde"}, true, []int{3, 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var totals []int
+			got := BuildWithProgress(func(total int) {
+				totals = append(totals, total)
+			}, func(sb *strings.Builder, report func(int)) {
+				for i, r := range tt.records {
+					sb.WriteString(r)
+					sb.WriteByte(',')
+					if i < len(tt.records)-1 || tt.reportEnd {
+						report(sb.Len())
+					}
+				}
+			})
+
+			if !reflect.DeepEqual(totals, tt.want) {
+				t.Errorf("BuildWithProgress() totals = %v, want %v", totals, tt.want)
+			}
+			for i := 1; i < len(totals); i++ {
+				if totals[i] < totals[i-1] {
+					t.Errorf("BuildWithProgress() totals not increasing: %v", totals)
+				}
+			}
+			if last := totals[len(totals)-1]; last != len(got) {
+				t.Errorf("BuildWithProgress() last total = %d, want len %d", last, len(got))
+			}
+		})
+	}
+}
+
+func TestBuildSeq(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want string
+	}{
+		{"empty", nil, ""},
+		{"one", []string{"hello"}, "hello"},
+		{"several", []string{"a", "", "bc", "世界"}, "abc世界"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildSeq(slices.Values(tt.in)); got != tt.want {
+				t.Errorf("BuildSeq() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSeqGenerator(t *testing.T) {
+	seq := func(yield func(string) bool) {
+		for i := 0; i < 3; i++ {
+			if !yield(SmallIntString(i)) {
+				return
+			}
+		}
+	}
+	if got := BuildSeq(seq); got != "012" {
+		t.Errorf("BuildSeq() = %q, want %q", got, "012")
+	}
+}
+
+func TestBuildOrError(t *testing.T) {
+	errTooLong := errors.New("too long")
+
+	tests := []struct {
+		name    string
+		capHint int
+		in      string
+		want    string
+		wantErr error
+	}{
+		{"success", 16, "hello", "hello", nil},
+		{"no hint", 0, "hello", "hello", nil},
+		{"negative hint", -1, "hello", "hello", nil},
+		{"error", 4, "too long for the limit", "", errTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := global.Stats()
+			got, err := BuildOrError(tt.capHint, func(sb *strings.Builder) error {
+				if tt.capHint > 0 && sb.Cap() < tt.capHint {
+					t.Errorf("builder Cap() = %v, want >= %v", sb.Cap(), tt.capHint)
+				}
+				sb.WriteString(tt.in)
+				if sb.Len() > 8 {
+					return errTooLong
+				}
+				return nil
+			})
+			after := global.Stats()
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("BuildOrError() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("BuildOrError() = %q, want %q", got, tt.want)
+			}
+			if n := after.Releases - before.Releases; n != 1 {
+				t.Errorf("BuildOrError() made %d Releases, want 1", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// BytesPool is the []byte companion of StringPool, for output
+// that is consumed as bytes, e.g. by hash.Hash.Write or a
+// network connection, where building a string would cost an
+// extra copy:
+//
+//	b := bp.Get()
+//	defer bp.Release(b)
+//	*b = append(*b, "hello"...)
+//	h.Write(*b)
+//
+// Unlike a strings.Builder, a released slice keeps its
+// backing array, which the next Get hands out again. The
+// caller must therefore not keep *b, or anything sliced from
+// it, after Release.
+//
+// The zero value is ready to use with DefaultMaxRetainedCap.
+// A BytesPool is safe for use by multiple goroutines
+// simultaneously and must not be copied after first use.
+type BytesPool struct {
+	// counters are accessed atomically and are kept first
+	// in the struct for 64-bit alignment.
+	gets     uint64
+	misses   uint64
+	discards uint64
+
+	pool   sync.Pool
+	maxCap int // 0 for DefaultMaxRetainedCap, < 0 for no limit
+}
+
+// NewBytesPool returns a BytesPool that drops released slices
+// whose capacity exceeds maxCap, so that one very large
+// output does not keep its memory alive in the pool. A maxCap
+// of zero or less means no limit.
+func NewBytesPool(maxCap int) *BytesPool {
+	if maxCap <= 0 {
+		maxCap = -1
+	}
+	return &BytesPool{maxCap: maxCap}
+}
+
+// Get returns a pointer to an empty byte slice, which may
+// have capacity left from an earlier use.
+func (p *BytesPool) Get() *[]byte {
+	atomic.AddUint64(&p.gets, 1)
+	if v := p.pool.Get(); v != nil {
+		return v.(*[]byte)
+	}
+	atomic.AddUint64(&p.misses, 1)
+	b := make([]byte, 0)
+	return &b
+}
+
+// Release truncates *b to zero length, keeping its capacity,
+// and parks it for reuse. Slices whose capacity exceeds the
+// pool's maximum are dropped instead. Releasing nil is a
+// no-op.
+func (p *BytesPool) Release(b *[]byte) {
+	if b == nil {
+		return
+	}
+	*b = (*b)[:0]
+
+	max := p.maxCap
+	if max == 0 {
+		max = DefaultMaxRetainedCap
+	}
+	if max > 0 && cap(*b) > max {
+		atomic.AddUint64(&p.discards, 1)
+		return
+	}
+	p.pool.Put(b)
+}
+
+// Stats returns a snapshot of the pool's counters. Only Gets,
+// Misses and Discards are counted.
+func (p *BytesPool) Stats() Stats {
+	return Stats{
+		Gets:     atomic.LoadUint64(&p.gets),
+		Misses:   atomic.LoadUint64(&p.misses),
+		Discards: atomic.LoadUint64(&p.discards),
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+)
+
+func TestBytesPool(t *testing.T) {
+	tests := []struct {
+		name string
+		pool *BytesPool
+	}{
+		{"zero value", &BytesPool{}},
+		{"new", NewBytesPool(1024)},
+		{"no limit", NewBytesPool(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.pool.Get()
+			if len(*b) != 0 {
+				t.Fatalf("Get() returned len %d, want 0", len(*b))
+			}
+			*b = append(*b, "hello, world"...)
+			c := cap(*b)
+			tt.pool.Release(b)
+
+			if len(*b) != 0 {
+				t.Errorf("Release() left len %d, want 0", len(*b))
+			}
+			if cap(*b) != c {
+				t.Errorf("Release() changed cap from %d to %d", c, cap(*b))
+			}
+
+			// sync.Pool may drop parked items, so reuse is not
+			// guaranteed; what comes back must be empty either way
+			again := tt.pool.Get()
+			if len(*again) != 0 {
+				t.Errorf("Get() after Release() returned len %d, want 0", len(*again))
+			}
+			if again == b && cap(*again) != c {
+				t.Errorf("reused slice cap = %d, want %d", cap(*again), c)
+			}
+		})
+	}
+}
+
+func TestBytesPoolMaxCap(t *testing.T) {
+	p := NewBytesPool(64)
+
+	b := p.Get()
+	*b = append(*b, strings.Repeat("x", 1024)...)
+	p.Release(b)
+
+	if got := p.Stats().Discards; got != 1 {
+		t.Errorf("Stats().Discards = %d, want 1", got)
+	}
+	if got := p.Get(); cap(*got) > 64 {
+		t.Errorf("Get().cap after dropping a large slice = %d, want <= 64", cap(*got))
+	}
+	p.Release(nil)
+}
+
+func TestBytesPoolHash(t *testing.T) {
+	p := NewBytesPool(0)
+	want := sha256.Sum256([]byte("key=value"))
+
+	for i := 0; i < 3; i++ {
+		b := p.Get()
+		*b = append(*b, "key="...)
+		*b = append(*b, "value"...)
+		if got := sha256.Sum256(*b); got != want {
+			t.Errorf("round %d: hash of pooled bytes differs", i)
+		}
+		p.Release(b)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"math"
+	"sort"
+	"sync/atomic"
+)
+
+// calibrateWarm is the number of builders Calibrate
+// preallocates into the pool.
+const calibrateWarm = 4
+
+// Calibrate sets the initial capacity of newly allocated
+// builders to the 90th percentile of sampleSizes and warms
+// the pool with a few builders of that capacity.
+//
+// sampleSizes is typically taken from offline profiling of
+// the lengths of strings built by the application, so that
+// most builders never have to grow. Negative sizes are
+// treated as zero. An empty sample leaves the pool
+// unchanged.
+//
+// Note that Release resets builders, which discards their
+// backing arrays, so the initial capacity applies to newly
+// allocated builders only.
+func (bp *StringPool) Calibrate(sampleSizes []int) {
+	if len(sampleSizes) == 0 {
+		return
+	}
+
+	n := percentile(sampleSizes, 0.9)
+	atomic.StoreInt64(&bp.initialCap, int64(n))
+
+	for i := 0; i < calibrateWarm; i++ {
+		// Put directly rather than Release, which would
+		// reset the builder and discard the capacity.
+		bp.park(bp.alloc())
+	}
+}
+
+// percentile returns the p-th percentile (0 < p <= 1) of
+// values using the nearest-rank method. values is not
+// modified. Negative values are treated as zero.
+func percentile(values []int, p float64) int {
+	sorted := make([]int, len(values))
+	copy(sorted, values)
+	sort.Ints(sorted)
+
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	if sorted[rank] < 0 {
+		return 0
+	}
+	return sorted[rank]
+}
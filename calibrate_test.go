@@ -0,0 +1,49 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "testing"
+
+func TestCalibrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []int
+		want    int
+	}{
+		{"empty", nil, 0},
+		{"single", []int{100}, 100},
+		{"ten", []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}, 90},
+		{"unsorted", []int{100, 10, 90, 20, 80, 30, 70, 40, 60, 50}, 90},
+		{"outlier", []int{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 1 << 20}, 5},
+		{"small sample", []int{1, 2, 3}, 3},
+		{"negative", []int{-5, -1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			p.Calibrate(tt.samples)
+
+			if got := p.initialCapacity(); got != tt.want {
+				t.Errorf("Calibrate() initial capacity = %v, want %v", got, tt.want)
+			}
+
+			// warmed and cold builders alike start at the
+			// calibrated capacity
+			for i := 0; i < 2*calibrateWarm; i++ {
+				if got := p.Get().Cap(); got < tt.want {
+					t.Errorf("Get().Cap() = %v, want >= %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCalibrateWarm(t *testing.T) {
+	p := New()
+	p.Calibrate([]int{64})
+	if got := p.Stats().News; got != calibrateWarm {
+		t.Errorf("Calibrate() allocated %v builders, want %v", got, calibrateWarm)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "fmt"
+
+// Reset policies reported by Config.
+const (
+	// ResetPolicyReset empties released builders.
+	ResetPolicyReset = "reset"
+
+	// ResetPolicyPoison fills released builders with poison
+	// bytes; see WithPoisonOnRelease.
+	ResetPolicyPoison = "poison"
+)
+
+// Config is the effective configuration of a StringPool, as
+// set by the options given to New and by Calibrate. It is
+// meant for display, e.g. by an admin endpoint.
+type Config struct {
+	// InitialCap is the capacity new builders are grown to.
+	InitialCap int `json:"initial_cap"`
+
+	// MaxRetainedCap is the largest capacity of a builder
+	// that Release parks; zero means no limit.
+	MaxRetainedCap int `json:"max_retained_cap"`
+
+	// MaxParked is the largest number of idle builders the
+	// pool keeps; zero means no limit. A pool backed by a
+	// sync.Pool is not bounded; the free list used on TinyGo
+	// is.
+	MaxParked int `json:"max_parked"`
+
+	// ResetPolicy is how Release clears builders, one of
+	// ResetPolicyReset and ResetPolicyPoison.
+	ResetPolicy string `json:"reset_policy"`
+
+	// RateLimit is the maximum number of Gets per second;
+	// zero means no limit.
+	RateLimit int `json:"rate_limit"`
+
+	// Debug reports whether the pool was created WithDebug.
+	Debug bool `json:"debug"`
+
+	// AdaptiveSizing reports whether the pool was created
+	// WithAdaptiveSizing.
+	AdaptiveSizing bool `json:"adaptive_sizing"`
+
+	// LeakDetection reports whether the pool was created
+	// WithLeakDetection.
+	LeakDetection bool `json:"leak_detection"`
+}
+
+// Config returns the pool's effective configuration.
+func (bp *StringPool) Config() Config {
+	c := Config{
+		InitialCap:     bp.initialCapacity(),
+		MaxRetainedCap: bp.maxRetainedCap(),
+		MaxParked:      bp.store().maxParked(),
+		ResetPolicy:    ResetPolicyReset,
+		Debug:          bp.debug != nil,
+		AdaptiveSizing: bp.adaptive != nil,
+		LeakDetection:  bp.leaks != nil,
+	}
+	if bp.poison {
+		c.ResetPolicy = ResetPolicyPoison
+	}
+	if bp.limiter != nil {
+		c.RateLimit = bp.limiter.perSecond
+	}
+	return c
+}
+
+// Validate reports whether the configuration is internally
+// consistent. It returns an error if new builders start
+// larger than the pool will retain, in which case every
+// builder is dropped on Release and the pool never reuses
+// anything.
+func (c Config) Validate() error {
+	if c.MaxRetainedCap > 0 && c.InitialCap > c.MaxRetainedCap {
+		return fmt.Errorf("stringpool: initial capacity %d exceeds max retained capacity %d", c.InitialCap, c.MaxRetainedCap)
+	}
+	return nil
+}
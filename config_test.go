@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "testing"
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		pool    *StringPool
+		want    Config
+		wantErr bool
+	}{
+		{"default", New(), Config{MaxRetainedCap: DefaultMaxRetainedCap, ResetPolicy: ResetPolicyReset}, false},
+		{
+			"several options",
+			New(WithInitialCap(256), WithMaxRetainedCap(4096), WithDebug(), WithRateLimit(1000)),
+			Config{InitialCap: 256, MaxRetainedCap: 4096, ResetPolicy: ResetPolicyReset, RateLimit: 1000, Debug: true},
+			false,
+		},
+		{
+			"no max cap",
+			New(WithMaxRetainedCap(0)),
+			Config{ResetPolicy: ResetPolicyReset},
+			false,
+		},
+		{"negative max cap", New(WithMaxRetainedCap(-1)), Config{ResetPolicy: ResetPolicyReset}, false},
+		{
+			"adaptive sizing",
+			New(WithAdaptiveSizing()),
+			Config{MaxRetainedCap: DefaultMaxRetainedCap, ResetPolicy: ResetPolicyReset, AdaptiveSizing: true},
+			false,
+		},
+		{
+			"leak detection",
+			New(WithLeakDetection()),
+			Config{MaxRetainedCap: DefaultMaxRetainedCap, ResetPolicy: ResetPolicyReset, LeakDetection: true},
+			false,
+		},
+		{
+			"initial cap exceeds max",
+			New(WithInitialCap(8192), WithMaxRetainedCap(4096)),
+			Config{InitialCap: 8192, MaxRetainedCap: 4096, ResetPolicy: ResetPolicyReset},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.pool.Config()
+			tt.want.MaxParked = tt.pool.store().maxParked() // depends on the build
+			if got != tt.want {
+				t.Errorf("Config() = %+v, want %+v", got, tt.want)
+			}
+			if err := got.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Config().Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigCalibrate(t *testing.T) {
+	p := New()
+	p.Calibrate([]int{100, 200, 300})
+
+	if got := p.Config().InitialCap; got == 0 {
+		t.Errorf("Config().InitialCap after Calibrate = 0, want > 0")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// debugState holds the bookkeeping of a pool created with
+// WithDebug.
+type debugState struct {
+	mu          sync.Mutex
+	seq         uint64
+	outstanding map[*strings.Builder]uint64 // builder -> Get sequence
+}
+
+// WithDebug enables diagnostic bookkeeping for the pool. It
+// adds a mutex-guarded map update to every Get and Release,
+// so it is intended for development and tests only.
+//
+// In debug mode the pool records the order in which builders
+// are handed out. Since sync.Pool favors recently released
+// items, releasing builders in the reverse order that they
+// were obtained tends to improve cache locality. Each Release
+// of a builder while a more recently obtained builder is
+// still outstanding increments Stats().OutOfOrderReleases.
+// The counter is purely advisory.
+func WithDebug() Option {
+	return func(bp *StringPool) {
+		bp.debug = &debugState{outstanding: make(map[*strings.Builder]uint64)}
+	}
+}
+
+// debugGet records that sb was handed out.
+func (bp *StringPool) debugGet(sb *strings.Builder) {
+	d := bp.debug
+	d.mu.Lock()
+	d.seq++
+	d.outstanding[sb] = d.seq
+	d.mu.Unlock()
+}
+
+// debugRelease records that sb was returned and checks
+// whether it was released out of LIFO order.
+func (bp *StringPool) debugRelease(sb *strings.Builder) {
+	d := bp.debug
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	seq, ok := d.outstanding[sb]
+	if !ok {
+		return
+	}
+	delete(d.outstanding, sb)
+
+	for _, other := range d.outstanding {
+		if other > seq {
+			atomic.AddUint64(&bp.stats.outOfOrder, 1)
+			return
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithDebugOutOfOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		debug   bool
+		order   []int // indexes of builders in release order
+		wantOOO uint64
+	}{
+		{"lifo", true, []int{2, 1, 0}, 0},
+		{"fifo", true, []int{0, 1, 2}, 2},
+		{"mixed", true, []int{1, 2, 0}, 1},
+		{"disabled", false, []int{0, 1, 2}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.debug {
+				opts = append(opts, WithDebug())
+			}
+			p := New(opts...)
+
+			sbs := []*strings.Builder{p.Get(), p.Get(), p.Get()}
+			for _, i := range tt.order {
+				p.Release(sbs[i])
+			}
+
+			if got := p.Stats().OutOfOrderReleases; got != tt.wantOOO {
+				t.Errorf("Stats().OutOfOrderReleases = %v, want %v", got, tt.wantOOO)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// WriteHTMLEscaped writes s into sb with the five characters
+// <, >, &, ' and " escaped, as html.EscapeString would return
+// it, without allocating an intermediate string.
+func WriteHTMLEscaped(sb *strings.Builder, s string) {
+	start := 0
+	for i := 0; i < len(s); i++ {
+		var esc string
+		switch s[i] {
+		case '<':
+			esc = "&lt;"
+		case '>':
+			esc = "&gt;"
+		case '&':
+			esc = "&amp;"
+		case '\'':
+			esc = "&#39;"
+		case '"':
+			esc = "&#34;"
+		default:
+			continue
+		}
+		sb.WriteString(s[start:i])
+		sb.WriteString(esc)
+		start = i + 1
+	}
+	sb.WriteString(s[start:])
+}
+
+// WriteJSONEscaped writes s into sb escaped as the body of a
+// JSON string, without the surrounding quotes, so that it
+// may be appended between quotes written by the caller.
+//
+// The output matches json.Marshal: quotes, backslashes and
+// control characters are escaped, as are <, >, & and the
+// line separators U+2028 and U+2029 so the result is safe to
+// embed in HTML. Invalid UTF-8 is replaced with U+FFFD.
+func WriteJSONEscaped(sb *strings.Builder, s string) {
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			sb.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				sb.WriteByte('\\')
+				sb.WriteByte(c)
+			case '\b':
+				sb.WriteString(`\b`)
+			case '\f':
+				sb.WriteString(`\f`)
+			case '\n':
+				sb.WriteString(`\n`)
+			case '\r':
+				sb.WriteString(`\r`)
+			case '\t':
+				sb.WriteString(`\t`)
+			default:
+				sb.WriteString(`\u00`)
+				sb.WriteByte(hexDigits[c>>4])
+				sb.WriteByte(hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			sb.WriteString(s[start:i])
+			sb.WriteString(`�`)
+		case r == '\u2028' || r == '\u2029':
+			sb.WriteString(s[start:i])
+			sb.WriteString(`\u202`)
+			sb.WriteByte(hexDigits[r&0xF])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	sb.WriteString(s[start:])
+}
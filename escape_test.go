@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"encoding/json"
+	"html"
+	"strings"
+	"testing"
+)
+
+// escapeInputs covers every escaped character of both sets.
+var escapeInputs = []struct {
+	name string
+	s    string
+}{
+	{"empty", ""},
+	{"plain", "nothing to escape"},
+	{"html", `<a href="x?a=1&b='2'">link</a>`},
+	{"only specials", `<>&'"`},
+	{"backslash", `C:\path\to\file`},
+	{"control", "a\x00b\x01c\x1f\x7f"},
+	{"whitespace", "tab\tnl\ncr\rbs\bff\f"},
+	{"multibyte", "héllo, 世界 🌍"},
+	{"line separators", "a\u2028b\u2029c"},
+	{"invalid utf8", "bad\xffbyte\xc3"},
+	{"everything", "<tag attr='v' other=\"w\">&amp;\\\x00\t\n\u2028é\xfe</tag>"},
+}
+
+func TestWriteHTMLEscaped(t *testing.T) {
+	for _, tt := range escapeInputs {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			sb.WriteString("prefix:")
+			WriteHTMLEscaped(sb, tt.s)
+
+			want := "prefix:" + html.EscapeString(tt.s)
+			if got := sb.String(); got != want {
+				t.Errorf("WriteHTMLEscaped() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestWriteJSONEscaped(t *testing.T) {
+	for _, tt := range escapeInputs {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			sb.WriteByte('"')
+			WriteJSONEscaped(sb, tt.s)
+			sb.WriteByte('"')
+
+			want, err := json.Marshal(tt.s)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if got := sb.String(); got != string(want) {
+				t.Errorf("WriteJSONEscaped() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestWriteEscapedAllocs(t *testing.T) {
+	s := strings.Repeat(`<a href="x">&'</a>`+"\n", 16)
+	sb := &strings.Builder{}
+	sb.Grow(8 * len(s))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		sb.Reset()
+		sb.Grow(8 * len(s))
+		WriteHTMLEscaped(sb, s)
+		WriteJSONEscaped(sb, s)
+	})
+	// the only allocation is the Grow after Reset
+	if allocs > 1 {
+		t.Errorf("WriteHTMLEscaped() and WriteJSONEscaped() allocated %v times, want <= 1", allocs)
+	}
+}
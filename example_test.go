@@ -136,7 +136,7 @@ func Exampleexample() {
 	// 124 + '|'
 	// 125 + '}'
 	// 126 + '~'
-	// 127 + '\u007f'
+	// 127 + '\x7f'
 	// 128 + '\u0080'
 	// 129 + '\u0081'
 	// 130 + '\u0082'
@@ -400,7 +400,7 @@ func ExampleExample() {
 	// 124 + '|'
 	// 125 + '}'
 	// 126 + '~'
-	// 127 + '\u007f'
+	// 127 + '\x7f'
 	// 128 + '\u0080'
 	// 129 + '\u0081'
 	// 130 + '\u0082'
@@ -0,0 +1,55 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"expvar"
+	"sync"
+	"sync/atomic"
+)
+
+// published maps the expvar names registered by PublishExpvar
+// to the pool each currently reports. expvar cannot replace
+// or remove a variable, so republishing a name re-points the
+// existing variable instead.
+//
+// The expvar.Func reads its pool from the atomic.Pointer and
+// never takes the mutex: expvar calls it while holding its own
+// lock, which PublishExpvar needs while holding the mutex.
+var published = struct {
+	sync.Mutex
+	m map[string]*atomic.Pointer[StringPool]
+}{m: make(map[string]*atomic.Pointer[StringPool])}
+
+// PublishExpvar publishes the pool's stats as the expvar
+// variable name, so that they appear on /debug/vars as a
+// JSON object with the keys of StatsJSON:
+//
+//	stringpool.New().PublishExpvar("stringpool.render")
+//
+// Publishing again under a name already used by
+// PublishExpvar makes the variable report this pool from
+// then on. A name taken by another expvar variable is misuse
+// and is handled according to SetStrictMode by not
+// publishing.
+func (bp *StringPool) PublishExpvar(name string) {
+	published.Lock()
+	defer published.Unlock()
+
+	if p, ok := published.m[name]; ok {
+		p.Store(bp)
+		return
+	}
+	if expvar.Get(name) != nil {
+		misuse("PublishExpvar: expvar %q is already in use", name)
+		return
+	}
+	p := new(atomic.Pointer[StringPool])
+	p.Store(bp)
+	published.m[name] = p
+	expvar.Publish(name, expvar.Func(func() any {
+		return p.Load().Stats()
+	}))
+}
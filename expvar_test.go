@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// expvarStats returns the stats published under name.
+func expvarStats(t *testing.T, name string) map[string]uint64 {
+	t.Helper()
+	v := expvar.Get(name)
+	if v == nil {
+		t.Fatalf("expvar.Get(%q) = nil", name)
+	}
+	var m map[string]uint64
+	if err := json.Unmarshal([]byte(v.String()), &m); err != nil {
+		t.Fatalf("expvar %q = %s: %v", name, v.String(), err)
+	}
+	return m
+}
+
+func TestPublishExpvar(t *testing.T) {
+	p := New(WithMaxRetainedCap(8))
+	p.PublishExpvar("stringpool.test")
+
+	a, b := p.Get(), p.Get()
+	b.WriteString("longer than eight")
+	p.Release(a)
+	p.Release(b)
+
+	m := expvarStats(t, "stringpool.test")
+	want := map[string]uint64{"gets": 2, "releases": 2, "misses": 2, "discards": 1}
+	for k, v := range want {
+		if m[k] != v {
+			t.Errorf("expvar %s = %d, want %d (all: %v)", k, m[k], v, m)
+		}
+	}
+}
+
+func TestPublishExpvarTwice(t *testing.T) {
+	first, second := New(), New()
+	first.PublishExpvar("stringpool.twice")
+	first.Get()
+
+	// no panic, and the variable now reports the second pool
+	second.PublishExpvar("stringpool.twice")
+	if got := expvarStats(t, "stringpool.twice")["gets"]; got != 0 {
+		t.Errorf("expvar gets = %d, want 0 from the second pool", got)
+	}
+}
+
+func TestPublishExpvarTaken(t *testing.T) {
+	if expvar.Get("stringpool.taken") == nil {
+		expvar.NewInt("stringpool.taken")
+	}
+	New().PublishExpvar("stringpool.taken")
+
+	if _, ok := expvar.Get("stringpool.taken").(*expvar.Int); !ok {
+		t.Errorf("PublishExpvar replaced a foreign expvar")
+	}
+
+	withStrictMode(true, func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("PublishExpvar() on a foreign name did not panic in strict mode")
+			}
+		}()
+		New().PublishExpvar("stringpool.taken")
+	})
+}
+
+// expvarSeq makes the names published by TestPublishExpvarDuringDo
+// unique across -count runs.
+var expvarSeq atomic.Int64
+
+func TestPublishExpvarDuringDo(t *testing.T) {
+	New().PublishExpvar("stringpool.during")
+
+	inDo := make(chan struct{})
+	done := make(chan struct{}, 2)
+	go func() {
+		first := true
+		expvar.Do(func(kv expvar.KeyValue) {
+			if first {
+				first = false
+				close(inDo)
+				// let PublishExpvar below block on expvar's lock
+				// before reading "stringpool.during"
+				time.Sleep(50 * time.Millisecond)
+			}
+			_ = kv.Value.String()
+		})
+		done <- struct{}{}
+	}()
+	<-inDo
+	go func() {
+		New().PublishExpvar(fmt.Sprintf("stringpool.during.%d", expvarSeq.Add(1)))
+		done <- struct{}{}
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("PublishExpvar and expvar.Do deadlocked")
+		}
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "fmt"
+
+// Sprintf formats according to a format specifier, as
+// fmt.Sprintf does, in a builder from the global pool and
+// returns the resulting string. It offers the familiar fmt
+// API to code that would otherwise call fmt.Sprintf and
+// then copy the result into a builder.
+//
+// The returned string remains valid after the builder is
+// reused: Release resets the builder, which detaches it from
+// the bytes the string refers to.
+func Sprintf(format string, args ...any) string {
+	sb := Get()
+	defer Release(sb)
+
+	fmt.Fprintf(sb, format, args...)
+	return sb.String()
+}
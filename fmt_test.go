@@ -0,0 +1,59 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSprintf(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		args   []any
+	}{
+		{"no args", "hello", nil},
+		{"verbs", "%d + %q = %v", []any{1, "two", 3.5}},
+		{"missing arg", "%d %d", []any{1}},
+		{"extra arg", "%d", []any{1, 2}},
+		{"struct", "%+v", []any{struct{ A int }{7}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := fmt.Sprintf(tt.format, tt.args...)
+			if got := Sprintf(tt.format, tt.args...); got != want {
+				t.Errorf("Sprintf() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestSprintfConcurrent(t *testing.T) {
+	const workers, rounds = 4, 500
+
+	results := make([][]string, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				results[w] = append(results[w], Sprintf("worker %d round %d", w, i))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// check only after the builders have been released and reused
+	for w, rs := range results {
+		for i, got := range rs {
+			if want := fmt.Sprintf("worker %d round %d", w, i); got != want {
+				t.Fatalf("Sprintf() = %q, want %q", got, want)
+			}
+		}
+	}
+}
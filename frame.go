@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "encoding/binary"
+
+// FrameHeaderLen is the length of the header that precedes
+// each frame written by a FrameBuilder.
+const FrameHeaderLen = 4
+
+// FrameBuilder writes length-prefixed frames: each frame is
+// a 4 byte big-endian header holding the length of the body
+// that follows it. StartFrame reserves room for the header
+// and EndFrame patches in the length once the body is
+// complete, so the body can be streamed without knowing its
+// size in advance:
+//
+//	fb := stringpool.NewFrameBuilder()
+//	defer fb.Release()
+//	fb.StartFrame()
+//	fb.WriteString("hello")
+//	fb.EndFrame()
+//	wire := fb.String() // "\x00\x00\x00\x05hello"
+//
+// Any number of frames may be written one after another.
+// The frames are assembled in a SeekableBuilder, whose
+// backing slice is pooled.
+type FrameBuilder struct {
+	buf   SeekableBuilder
+	start int // offset of the open frame's header, or -1
+}
+
+// NewFrameBuilder returns an empty FrameBuilder.
+func NewFrameBuilder() *FrameBuilder {
+	return &FrameBuilder{start: -1}
+}
+
+// StartFrame begins a new frame by reserving its header.
+// Frames do not nest; starting a frame while another is
+// open is misuse and is handled according to
+// SetStrictMode by ignoring the call.
+func (f *FrameBuilder) StartFrame() {
+	if f.start >= 0 {
+		misuse("FrameBuilder.StartFrame: frame already started")
+		return
+	}
+	f.start = f.buf.Len()
+	var hdr [FrameHeaderLen]byte
+	f.buf.Write(hdr[:])
+}
+
+// Write appends p to the body of the open frame. It always
+// returns len(p) and a nil error.
+func (f *FrameBuilder) Write(p []byte) (int, error) {
+	return f.buf.Write(p)
+}
+
+// WriteString appends s to the body of the open frame. It
+// always returns len(s) and a nil error.
+func (f *FrameBuilder) WriteString(s string) (int, error) {
+	return f.buf.WriteString(s)
+}
+
+// EndFrame completes the open frame by writing the length
+// of its body into the header reserved by StartFrame.
+// Calling EndFrame with no open frame is misuse and is
+// handled according to SetStrictMode by ignoring the call.
+func (f *FrameBuilder) EndFrame() {
+	if f.start < 0 {
+		misuse("FrameBuilder.EndFrame: no frame started")
+		return
+	}
+	var hdr [FrameHeaderLen]byte
+	binary.BigEndian.PutUint32(hdr[:], uint32(f.buf.Len()-f.start-FrameHeaderLen))
+	f.buf.WriteAt(hdr[:], int64(f.start))
+	f.start = -1
+}
+
+// Len returns the number of bytes written so far, headers
+// included.
+func (f *FrameBuilder) Len() int {
+	return f.buf.Len()
+}
+
+// String returns the frames written so far. The header of a
+// frame that has not been ended holds zero.
+func (f *FrameBuilder) String() string {
+	return f.buf.String()
+}
+
+// Release returns the backing slice to its pool and empties
+// the FrameBuilder, which may be reused afterwards.
+func (f *FrameBuilder) Release() {
+	f.buf.Release()
+	f.start = -1
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestFrameBuilder(t *testing.T) {
+	tests := []struct {
+		name   string
+		bodies []string
+		want   string
+	}{
+		{"none", nil, ""},
+		{"empty frame", []string{""}, "\x00\x00\x00\x00"},
+		{"one frame", []string{"hello"}, "\x00\x00\x00\x05hello"},
+		{"two frames", []string{"ab", "cde"}, "\x00\x00\x00\x02ab\x00\x00\x00\x03cde"},
+		{"empty between", []string{"a", "", "b"}, "\x00\x00\x00\x01a\x00\x00\x00\x00\x00\x00\x00\x01b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fb := NewFrameBuilder()
+			defer fb.Release()
+
+			for _, body := range tt.bodies {
+				fb.StartFrame()
+				fb.WriteString(body)
+				fb.EndFrame()
+			}
+			if got := fb.String(); got != tt.want {
+				t.Errorf("FrameBuilder.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrameBuilderLargeBody(t *testing.T) {
+	fb := NewFrameBuilder()
+	defer fb.Release()
+
+	body := strings.Repeat("0123456789", 10000)
+	fb.StartFrame()
+	for i := 0; i < len(body); i += 10 {
+		fb.Write([]byte(body[i : i+10]))
+	}
+	fb.EndFrame()
+
+	s := fb.String()
+	if got := binary.BigEndian.Uint32([]byte(s[:FrameHeaderLen])); got != uint32(len(body)) {
+		t.Errorf("frame header = %v, want %v", got, len(body))
+	}
+	if s[FrameHeaderLen:] != body {
+		t.Errorf("frame body differs from what was written")
+	}
+}
+
+func TestFrameBuilderMisuse(t *testing.T) {
+	fb := NewFrameBuilder()
+	defer fb.Release()
+
+	fb.EndFrame()
+	if got := fb.Len(); got != 0 {
+		t.Errorf("EndFrame() without StartFrame() wrote %d bytes, want 0", got)
+	}
+
+	withStrictMode(true, func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("nested StartFrame() did not panic in strict mode")
+			}
+		}()
+		fb.StartFrame()
+		fb.StartFrame()
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+//go:build tinygo || stringpool_freelist
+// +build tinygo stringpool_freelist
+
+package stringpool
+
+import (
+	"strings"
+	"sync"
+)
+
+// freeListMax is the most idle builders a free list keeps.
+// Unlike a sync.Pool, a free list is never emptied by the
+// garbage collector, so it is bounded instead.
+const freeListMax = 128
+
+// builderStore holds the idle builders of a StringPool. This
+// implementation is a mutex-guarded free list, used on TinyGo
+// and in builds with the stringpool_freelist tag, where
+// sync.Pool is unavailable, behaves differently or makes
+// reuse nondeterministic. Builders are reused last in, first
+// out.
+//
+// Only StringPool uses the free list; the slice pools behind
+// GridBuilder and SeekableBuilder remain sync.Pools.
+type builderStore struct {
+	mu   sync.Mutex
+	free []*strings.Builder
+}
+
+// get returns an idle builder, or nil if there is none.
+func (s *builderStore) get() *strings.Builder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.free)
+	if n == 0 {
+		return nil
+	}
+	sb := s.free[n-1]
+	s.free[n-1] = nil
+	s.free = s.free[:n-1]
+	return sb
+}
+
+// put parks sb for reuse, or drops it if the free list is
+// full. It reports whether sb was parked.
+func (s *builderStore) put(sb *strings.Builder) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.free) >= freeListMax {
+		return false
+	}
+	s.free = append(s.free, sb)
+	return true
+}
+
+// maxParked returns the most idle builders the store keeps.
+func (s *builderStore) maxParked() int {
+	return freeListMax
+}
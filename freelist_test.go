@@ -0,0 +1,126 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+//go:build tinygo || stringpool_freelist
+// +build tinygo stringpool_freelist
+
+package stringpool
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFreeListReuse(t *testing.T) {
+	p := New()
+
+	a := p.Get()
+	a.WriteString("hello")
+	p.Release(a)
+
+	b := p.Get()
+	if b != a {
+		t.Errorf("Get() after Release() returned a new builder, want the released one")
+	}
+	if b.Len() != 0 {
+		t.Errorf("reused builder Len() = %d, want 0", b.Len())
+	}
+	if got := p.Stats().Misses; got != 1 {
+		t.Errorf("Stats().Misses = %d, want 1", got)
+	}
+}
+
+func TestFreeListReuseForeign(t *testing.T) {
+	p := New()
+
+	a := &strings.Builder{}
+	a.WriteString("made before the pool")
+	p.Release(a)
+
+	b := p.Get()
+	if b != a {
+		t.Errorf("Get() after foreign Release() returned a new builder, want the released one")
+	}
+	if b.Len() != 0 {
+		t.Errorf("reused builder Len() = %d, want 0", b.Len())
+	}
+	if got := p.Stats().Misses; got != 0 {
+		t.Errorf("Stats().Misses = %d, want 0", got)
+	}
+}
+
+func TestFreeListOrder(t *testing.T) {
+	p := New()
+
+	sbs := []*strings.Builder{p.Get(), p.Get(), p.Get()}
+	for _, sb := range sbs {
+		p.Release(sb)
+	}
+	// last in, first out
+	for i := len(sbs) - 1; i >= 0; i-- {
+		if got := p.Get(); got != sbs[i] {
+			t.Errorf("Get() returned builder %p, want %p", got, sbs[i])
+		}
+	}
+	if got := p.Stats().Misses; got != 3 {
+		t.Errorf("Stats().Misses = %d, want 3", got)
+	}
+}
+
+func TestFreeListBounded(t *testing.T) {
+	p := New()
+
+	sbs := make([]*strings.Builder, freeListMax+10)
+	for i := range sbs {
+		sbs[i] = p.Get()
+	}
+	for _, sb := range sbs {
+		p.Release(sb)
+	}
+	if got := len(p.store().free); got != freeListMax {
+		t.Errorf("free list holds %d builders, want %d", got, freeListMax)
+	}
+
+	// the builders dropped by the full list are discards and
+	// are not retained
+	if got := p.Stats().Discards; got != 10 {
+		t.Errorf("Stats().Discards = %d, want %d", got, 10)
+	}
+	if got, want := p.RetainedBytes(), int64(freeListMax)*builderSize; got != want {
+		t.Errorf("RetainedBytes() = %d, want %d", got, want)
+	}
+}
+
+func TestFreeListConcurrent(t *testing.T) {
+	p := New()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				sb := p.Get()
+				if sb.Len() != 0 {
+					t.Errorf("Get() returned a builder holding %q", sb.String())
+					return
+				}
+				sb.WriteString("x")
+				p.Release(sb)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := p.Stats().Misses; got > 8 {
+		t.Errorf("Stats().Misses = %d, want at most one per goroutine", got)
+	}
+}
+
+func TestFreeListConfig(t *testing.T) {
+	if got := New().Config().MaxParked; got != freeListMax {
+		t.Errorf("Config().MaxParked = %d, want %d", got, freeListMax)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+// gridPool holds the backing byte slices used by GridBuilder.
+var gridPool BytesPool
+
+// GridBuilder builds fixed-layout text on a grid of
+// rows x cols bytes that starts out filled with spaces.
+// Strings are placed at absolute positions with Set and
+// the grid is rendered with String.
+//
+// This is a pooled alternative to building a report or
+// terminal screen with lots of padding math. Columns are
+// byte columns; content is expected to be ASCII.
+//
+// Call Release when the grid is no longer needed to return
+// its backing slice to the pool. A GridBuilder must not be
+// used after Release.
+type GridBuilder struct {
+	rows, cols int
+	buf        *[]byte
+}
+
+// NewGridBuilder returns a GridBuilder with the given number
+// of rows and columns, filled with spaces. Negative sizes are
+// treated as zero.
+func NewGridBuilder(rows, cols int) *GridBuilder {
+	if rows < 0 {
+		rows = 0
+	}
+	if cols < 0 {
+		cols = 0
+	}
+
+	buf := gridPool.Get()
+	n := rows * cols
+	if cap(*buf) < n {
+		*buf = make([]byte, n)
+	}
+	*buf = (*buf)[:n]
+	for i := range *buf {
+		(*buf)[i] = ' '
+	}
+
+	return &GridBuilder{rows: rows, cols: cols, buf: buf}
+}
+
+// Rows returns the number of rows in the grid.
+func (g *GridBuilder) Rows() int { return g.rows }
+
+// Cols returns the number of columns in the grid.
+func (g *GridBuilder) Cols() int { return g.cols }
+
+// Set writes s into the grid starting at (row, col).
+//
+// Positions outside of the grid are ignored and any part
+// of s that would extend past the end of the row is
+// clipped; Set never wraps onto the next row.
+func (g *GridBuilder) Set(row, col int, s string) {
+	if row < 0 || row >= g.rows || col < 0 || col >= g.cols {
+		return
+	}
+	if n := g.cols - col; len(s) > n {
+		s = s[:n]
+	}
+	copy((*g.buf)[row*g.cols+col:], s)
+}
+
+// String renders the grid, one line per row, each
+// terminated by a newline. The rendering is assembled in
+// a pooled strings.Builder.
+func (g *GridBuilder) String() string {
+	sb := Get()
+	defer Release(sb)
+
+	sb.Grow(g.rows * (g.cols + 1))
+	for r := 0; r < g.rows; r++ {
+		sb.Write((*g.buf)[r*g.cols : (r+1)*g.cols])
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Release returns the grid's backing slice to the pool.
+func (g *GridBuilder) Release() {
+	if g.buf == nil {
+		return
+	}
+	gridPool.Release(g.buf)
+	g.buf = nil
+	g.rows, g.cols = 0, 0
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "testing"
+
+func TestGridBuilder(t *testing.T) {
+	type pos struct {
+		row, col int
+		s        string
+	}
+	tests := []struct {
+		name       string
+		rows, cols int
+		set        []pos
+		want       string
+	}{
+		{"empty", 0, 0, nil, ""},
+		{"blank", 2, 3, nil, "   \n   \n"},
+		{"origin", 2, 4, []pos{{0, 0, "ab"}}, "ab  \n    \n"},
+		{"corners", 2, 4, []pos{{0, 0, "a"}, {0, 3, "b"}, {1, 0, "c"}, {1, 3, "d"}}, "a  b\nc  d\n"},
+		{"middle", 3, 5, []pos{{1, 1, "xyz"}}, "     \n xyz \n     \n"},
+		{"overwrite", 1, 5, []pos{{0, 0, "hello"}, {0, 1, "EL"}}, "hELlo\n"},
+		{"clipped", 1, 4, []pos{{0, 2, "abcdef"}}, "  ab\n"},
+		{"negative row", 1, 3, []pos{{-1, 0, "a"}}, "   \n"},
+		{"negative col", 1, 3, []pos{{0, -1, "a"}}, "   \n"},
+		{"row out of range", 1, 3, []pos{{1, 0, "a"}}, "   \n"},
+		{"col out of range", 1, 3, []pos{{0, 3, "a"}}, "   \n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGridBuilder(tt.rows, tt.cols)
+			defer g.Release()
+
+			for _, p := range tt.set {
+				g.Set(p.row, p.col, p.s)
+			}
+			if got := g.String(); got != tt.want {
+				t.Errorf("GridBuilder.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGridBuilderReuse(t *testing.T) {
+	g := NewGridBuilder(1, 4)
+	g.Set(0, 0, "dirt")
+	g.Release()
+
+	// a reused backing slice must be blanked again
+	g = NewGridBuilder(1, 4)
+	defer g.Release()
+	if got := g.String(); got != "    \n" {
+		t.Errorf("reused GridBuilder.String() = %q, want %q", got, "    \n")
+	}
+}
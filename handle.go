@@ -0,0 +1,148 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"io"
+	"strings"
+)
+
+var _ io.Closer = (*Handle)(nil)
+
+// Handle wraps a pooled strings.Builder and ties its release
+// to a Close method:
+//
+//	h := stringpool.GetHandle()
+//	defer h.Close()
+//	h.WriteString("hello")
+//	s := h.String()
+//
+// Close returns the builder to its pool and detaches it from
+// the Handle. Using the Handle after Close never touches a
+// builder that is back in the pool; its behavior depends on
+// SetStrictMode:
+//
+//   - by default, writes return ErrClosed, String returns
+//     the empty string and Len returns zero;
+//   - in strict mode, these panic, which helps find
+//     use-after-release bugs during development.
+type Handle struct {
+	*strings.Builder
+	pool *StringPool
+}
+
+// GetHandle returns a Handle wrapping an empty builder from
+// the global pool.
+func GetHandle() *Handle {
+	return Default().GetHandle()
+}
+
+// GetHandle returns a Handle wrapping an empty builder from
+// the pool.
+func (bp *StringPool) GetHandle() *Handle {
+	return &Handle{Builder: bp.Get(), pool: bp}
+}
+
+// String returns the accumulated string. After Close, it
+// returns the empty string, or panics in strict mode.
+func (h *Handle) String() string {
+	if h.closed("String") {
+		return ""
+	}
+	return h.Builder.String()
+}
+
+// Close releases the builder back to the pool. It is safe
+// to call Close more than once; later calls do nothing.
+// Close always returns nil.
+func (h *Handle) Close() error {
+	if h.Builder == nil {
+		return nil
+	}
+	h.pool.Release(h.Builder)
+	h.Builder = nil
+	return nil
+}
+
+// closed reports whether the Handle has been closed, reporting
+// misuse by op if it has.
+func (h *Handle) closed(op string) bool {
+	if h.Builder == nil {
+		misuse("Handle.%s: handle is closed", op)
+		return true
+	}
+	return false
+}
+
+// Write appends p to the builder. After Close it returns
+// ErrClosed.
+func (h *Handle) Write(p []byte) (int, error) {
+	if h.closed("Write") {
+		return 0, ErrClosed
+	}
+	return h.Builder.Write(p)
+}
+
+// WriteString appends s to the builder. After Close it
+// returns ErrClosed.
+func (h *Handle) WriteString(s string) (int, error) {
+	if h.closed("WriteString") {
+		return 0, ErrClosed
+	}
+	return h.Builder.WriteString(s)
+}
+
+// WriteByte appends c to the builder. After Close it returns
+// ErrClosed.
+func (h *Handle) WriteByte(c byte) error {
+	if h.closed("WriteByte") {
+		return ErrClosed
+	}
+	return h.Builder.WriteByte(c)
+}
+
+// WriteRune appends the UTF-8 encoding of r to the builder.
+// After Close it returns ErrClosed.
+func (h *Handle) WriteRune(r rune) (int, error) {
+	if h.closed("WriteRune") {
+		return 0, ErrClosed
+	}
+	return h.Builder.WriteRune(r)
+}
+
+// Grow grows the builder's capacity. After Close it does
+// nothing.
+func (h *Handle) Grow(n int) {
+	if h.closed("Grow") {
+		return
+	}
+	h.Builder.Grow(n)
+}
+
+// Len returns the number of accumulated bytes. After Close it
+// returns zero.
+func (h *Handle) Len() int {
+	if h.closed("Len") {
+		return 0
+	}
+	return h.Builder.Len()
+}
+
+// Cap returns the capacity of the builder. After Close it
+// returns zero.
+func (h *Handle) Cap() int {
+	if h.closed("Cap") {
+		return 0
+	}
+	return h.Builder.Cap()
+}
+
+// Reset empties the builder. After Close it does nothing.
+func (h *Handle) Reset() {
+	if h.closed("Reset") {
+		return
+	}
+	h.Builder.Reset()
+}
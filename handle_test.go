@@ -0,0 +1,110 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestHandle(t *testing.T) {
+	h := New().GetHandle()
+	h.WriteString("hello, ")
+	h.WriteString("handle")
+	if got, want := h.String(), "hello, handle"; got != want {
+		t.Errorf("Handle.String() = %q, want %q", got, want)
+	}
+	if err := h.Close(); err != nil {
+		t.Errorf("Handle.Close() error = %v, want nil", err)
+	}
+	if err := h.Close(); err != nil {
+		t.Errorf("second Handle.Close() error = %v, want nil", err)
+	}
+}
+
+func TestHandleStringAfterClose(t *testing.T) {
+	tests := []struct {
+		name      string
+		strict    bool
+		wantPanic bool
+	}{
+		{"lenient", false, false},
+		{"strict", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := GetHandle()
+			h.WriteString("released")
+			h.Close()
+
+			withStrictMode(tt.strict, func() {
+				defer func() {
+					if r := recover(); (r != nil) != tt.wantPanic {
+						t.Errorf("Handle.String() after Close panic = %v, wantPanic %v", r, tt.wantPanic)
+					}
+				}()
+				if got := h.String(); got != "" {
+					t.Errorf("Handle.String() after Close = %q, want %q", got, "")
+				}
+			})
+		})
+	}
+}
+
+func TestHandleWriteAfterClose(t *testing.T) {
+	p := New()
+	h := p.GetHandle()
+	h.WriteString("hello")
+	sb := h.Builder
+	h.Close()
+
+	tests := []struct {
+		name  string
+		write func() error
+	}{
+		{"Write", func() error { _, err := h.Write([]byte("x")); return err }},
+		{"WriteString", func() error { _, err := h.WriteString("x"); return err }},
+		{"WriteByte", func() error { return h.WriteByte('x') }},
+		{"WriteRune", func() error { _, err := h.WriteRune('x'); return err }},
+		{"Fprintf", func() error { _, err := fmt.Fprintf(h, "%d", 1); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.write(); !errors.Is(err, ErrClosed) {
+				t.Errorf("%s after Close error = %v, want %v", tt.name, err, ErrClosed)
+			}
+		})
+	}
+
+	h.Grow(64)
+	h.Reset()
+	if h.Len() != 0 || h.Cap() != 0 {
+		t.Errorf("Len(), Cap() after Close = %d, %d, want 0, 0", h.Len(), h.Cap())
+	}
+
+	// the released builder was not written to, so whoever
+	// Gets it next sees an empty builder
+	if sb.Len() != 0 {
+		t.Errorf("released builder holds %q after writes to the closed handle", sb.String())
+	}
+	if got := p.Get(); got.Len() != 0 {
+		t.Errorf("Get() after Close returned a builder holding %q", got.String())
+	}
+}
+
+func TestHandleWriteAfterCloseStrict(t *testing.T) {
+	h := GetHandle()
+	h.Close()
+
+	withStrictMode(true, func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("WriteString() after Close did not panic in strict mode")
+			}
+		}()
+		h.WriteString("x")
+	})
+}
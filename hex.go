@@ -0,0 +1,61 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "strings"
+
+// WriteXXD writes a hex dump of data into sb in the format
+// of the xxd command with bytesPerLine bytes per line
+// (xxd -c bytesPerLine):
+//
+//	00000000: 4865 6c6c 6f2c 2077  Hello, w
+//	00000008: 6f72 6c64 0a         orld.
+//
+// Each line holds the offset, the bytes in hex in groups
+// of two, and the printable ASCII characters, with other
+// bytes shown as '.'. A partial final line is padded so that
+// its ASCII column lines up. Empty data writes nothing.
+//
+// bytesPerLine must be greater than zero; other values are
+// misuse and are handled according to SetStrictMode.
+func WriteXXD(sb *strings.Builder, data []byte, bytesPerLine int) {
+	if bytesPerLine <= 0 {
+		misuse("WriteXXD: bytesPerLine must be > 0, got %d", bytesPerLine)
+		return
+	}
+
+	for off := 0; off < len(data); off += bytesPerLine {
+		line := data[off:]
+		if len(line) > bytesPerLine {
+			line = line[:bytesPerLine]
+		}
+
+		for shift := 28; shift >= 0; shift -= 4 {
+			sb.WriteByte(hexDigits[(off>>uint(shift))&0xF])
+		}
+		sb.WriteString(": ")
+
+		for i := 0; i < bytesPerLine; i++ {
+			if i > 0 && i%2 == 0 {
+				sb.WriteByte(' ')
+			}
+			if i < len(line) {
+				sb.WriteByte(hexDigits[line[i]>>4])
+				sb.WriteByte(hexDigits[line[i]&0xF])
+			} else {
+				sb.WriteString("  ")
+			}
+		}
+
+		sb.WriteString("  ")
+		for _, c := range line {
+			if c < 0x20 || c > 0x7e {
+				c = '.'
+			}
+			sb.WriteByte(c)
+		}
+		sb.WriteByte('\n')
+	}
+}
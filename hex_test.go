@@ -0,0 +1,63 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteXXD(t *testing.T) {
+	const text = "Hello, world!\nThis is xxd."
+
+	// expected output generated with xxd -c N
+	tests := []struct {
+		name         string
+		data         []byte
+		bytesPerLine int
+		want         string
+	}{
+		{"empty", nil, 16, ""},
+		{"8 per line", []byte(text), 8, "" +
+			"00000000: 4865 6c6c 6f2c 2077  Hello, w\n" +
+			"00000008: 6f72 6c64 210a 5468  orld!.Th\n" +
+			"00000010: 6973 2069 7320 7878  is is xx\n" +
+			"00000018: 642e                 d.\n"},
+		{"16 per line", []byte(text), 16, "" +
+			"00000000: 4865 6c6c 6f2c 2077 6f72 6c64 210a 5468  Hello, world!.Th\n" +
+			"00000010: 6973 2069 7320 7878 642e                 is is xxd.\n"},
+		{"odd width", []byte("abc"), 5, "00000000: 6162 63       abc\n"},
+		{"exact line", []byte("abcd"), 4, "00000000: 6162 6364  abcd\n"},
+		{"one per line", []byte{0x00, 0xff}, 1, "" +
+			"00000000: 00  .\n" +
+			"00000001: ff  .\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			WriteXXD(sb, tt.data, tt.bytesPerLine)
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteXXD() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteXXDInvalidWidth(t *testing.T) {
+	sb := &strings.Builder{}
+	WriteXXD(sb, []byte("abc"), 0)
+	if got := sb.String(); got != "" {
+		t.Errorf("WriteXXD() with zero width = %q, want %q", got, "")
+	}
+
+	withStrictMode(true, func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("WriteXXD() did not panic for a zero width in strict mode")
+			}
+		}()
+		WriteXXD(sb, []byte("abc"), 0)
+	})
+}
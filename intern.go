@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"sync"
+)
+
+// Interner builds strings in pooled builders and
+// deduplicates them, so that identical built strings share
+// a single canonical copy. This bounds memory when many
+// repetitive strings are generated, e.g. while tokenizing.
+//
+// The zero value is ready to use and draws builders from
+// the global pool. An Interner is safe for use by multiple
+// goroutines simultaneously.
+type Interner struct {
+	mu   sync.Mutex
+	m    map[string]string
+	pool *StringPool
+}
+
+// NewInterner returns an Interner that draws its builders
+// from the pool.
+func (bp *StringPool) NewInterner() *Interner {
+	return &Interner{pool: bp}
+}
+
+// Build calls fn to build a string and returns the canonical
+// copy of the result: the first string with the same
+// contents that was built by the Interner since it was
+// created or last cleared.
+func (in *Interner) Build(fn func(sb *strings.Builder)) string {
+	p := in.pool
+	if p == nil {
+		p = Default()
+	}
+
+	sb := p.Get()
+	defer p.Release(sb)
+
+	fn(sb)
+	s := sb.String()
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if c, ok := in.m[s]; ok {
+		return c
+	}
+	if in.m == nil {
+		in.m = make(map[string]string)
+	}
+	// s shares the builder's backing array, which may be much
+	// larger than s; keep only a copy of its bytes.
+	s = strings.Clone(s)
+	in.m[s] = s
+	return s
+}
+
+// Len returns the number of distinct strings held.
+func (in *Interner) Len() int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return len(in.m)
+}
+
+// Clear discards all interned strings. Strings previously
+// returned by Build remain valid.
+func (in *Interner) Clear() {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.m = nil
+}
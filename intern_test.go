@@ -0,0 +1,85 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// sameBacking reports whether a and b share a backing array.
+func sameBacking(a, b string) bool {
+	ha := (*reflect.StringHeader)(unsafe.Pointer(&a))
+	hb := (*reflect.StringHeader)(unsafe.Pointer(&b))
+	return ha.Len > 0 && ha.Len == hb.Len && ha.Data == hb.Data
+}
+
+func TestInterner(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *Interner
+	}{
+		{"zero value", &Interner{}},
+		{"newPool", New().NewInterner()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := func(parts ...string) string {
+				return tt.in.Build(func(sb *strings.Builder) {
+					for _, p := range parts {
+						sb.WriteString(p)
+					}
+				})
+			}
+
+			a := build("tok", "en")
+			b := build("to", "ken")
+			c := build("other")
+
+			if a != "token" || b != "token" || c != "other" {
+				t.Fatalf("Interner.Build() = %q, %q, %q, want %q, %q, %q", a, b, c, "token", "token", "other")
+			}
+			if !sameBacking(a, b) {
+				t.Errorf("Interner.Build() returned distinct copies of %q", a)
+			}
+			if got := tt.in.Len(); got != 2 {
+				t.Errorf("Interner.Len() = %v, want %v", got, 2)
+			}
+
+			tt.in.Clear()
+			if got := tt.in.Len(); got != 0 {
+				t.Errorf("Interner.Len() after Clear() = %v, want %v", got, 0)
+			}
+			if a != "token" {
+				t.Errorf("string after Clear() = %q, want %q", a, "token")
+			}
+
+			d := build("token")
+			if sameBacking(a, d) {
+				t.Errorf("Interner.Build() after Clear() returned a cleared copy")
+			}
+			if got := tt.in.Len(); got != 1 {
+				t.Errorf("Interner.Len() = %v, want %v", got, 1)
+			}
+		})
+	}
+}
+
+func TestInternerCopiesResult(t *testing.T) {
+	var built string
+	got := New().NewInterner().Build(func(sb *strings.Builder) {
+		sb.Grow(4096)
+		sb.WriteString("token")
+		built = sb.String()
+	})
+	if got != "token" {
+		t.Fatalf("Interner.Build() = %q, want %q", got, "token")
+	}
+	if sameBacking(got, built) {
+		t.Errorf("Interner.Build() kept the builder's backing array")
+	}
+}
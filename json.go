@@ -0,0 +1,169 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// WriteObject writes a JSON object built from alternating
+// key and value arguments into sb:
+//
+//	WriteObject(sb, "name", "gopher", "age", 12)
+//	// {"name":"gopher","age":12}
+//
+// Keys must be strings. Strings, booleans, integers, floats
+// and nil are written directly; other values are encoded
+// with encoding/json.
+//
+// Misuse is handled according to SetStrictMode: an odd
+// number of arguments, a non-string key or a value that
+// cannot be encoded panics in strict mode; otherwise the
+// trailing key or the offending pair is skipped.
+func WriteObject(sb *strings.Builder, kv ...interface{}) {
+	if len(kv)%2 != 0 {
+		misuse("WriteObject: odd number of arguments (%d)", len(kv))
+		kv = kv[:len(kv)-1]
+	}
+
+	sb.WriteByte('{')
+	first := true
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			misuse("WriteObject: non-string key %v (%T)", kv[i], kv[i])
+			continue
+		}
+
+		var raw []byte
+		if !isSimpleJSON(kv[i+1]) {
+			var err error
+			if raw, err = json.Marshal(kv[i+1]); err != nil {
+				misuse("WriteObject: key %q: %v", key, err)
+				continue
+			}
+		}
+
+		if !first {
+			sb.WriteByte(',')
+		}
+		first = false
+
+		writeJSONString(sb, key)
+		sb.WriteByte(':')
+		if raw != nil {
+			sb.Write(raw)
+		} else {
+			writeSimpleJSON(sb, kv[i+1])
+		}
+	}
+	sb.WriteByte('}')
+}
+
+// isSimpleJSON reports whether v is written directly by
+// writeSimpleJSON rather than through encoding/json.
+func isSimpleJSON(v interface{}) bool {
+	switch v := v.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return true
+	case float32:
+		return !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0)
+	case float64:
+		return !math.IsNaN(v) && !math.IsInf(v, 0)
+	}
+	return false
+}
+
+// writeSimpleJSON writes v, which must satisfy isSimpleJSON,
+// as a JSON value into sb.
+func writeSimpleJSON(sb *strings.Builder, v interface{}) {
+	var buf [32]byte
+	switch v := v.(type) {
+	case nil:
+		sb.WriteString("null")
+	case string:
+		writeJSONString(sb, v)
+	case bool:
+		sb.Write(strconv.AppendBool(buf[:0], v))
+	case int:
+		sb.Write(strconv.AppendInt(buf[:0], int64(v), 10))
+	case int8:
+		sb.Write(strconv.AppendInt(buf[:0], int64(v), 10))
+	case int16:
+		sb.Write(strconv.AppendInt(buf[:0], int64(v), 10))
+	case int32:
+		sb.Write(strconv.AppendInt(buf[:0], int64(v), 10))
+	case int64:
+		sb.Write(strconv.AppendInt(buf[:0], v, 10))
+	case uint:
+		sb.Write(strconv.AppendUint(buf[:0], uint64(v), 10))
+	case uint8:
+		sb.Write(strconv.AppendUint(buf[:0], uint64(v), 10))
+	case uint16:
+		sb.Write(strconv.AppendUint(buf[:0], uint64(v), 10))
+	case uint32:
+		sb.Write(strconv.AppendUint(buf[:0], uint64(v), 10))
+	case uint64:
+		sb.Write(strconv.AppendUint(buf[:0], v, 10))
+	case float32:
+		sb.Write(strconv.AppendFloat(buf[:0], float64(v), 'g', -1, 32))
+	case float64:
+		sb.Write(strconv.AppendFloat(buf[:0], v, 'g', -1, 64))
+	}
+}
+
+const hexDigits = "0123456789abcdef"
+
+// writeJSONString writes s into sb as a quoted JSON string,
+// escaping quotes, backslashes and control characters.
+// Invalid UTF-8 is replaced with U+FFFD.
+func writeJSONString(sb *strings.Builder, s string) {
+	sb.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			sb.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				sb.WriteByte('\\')
+				sb.WriteByte(c)
+			case '\n':
+				sb.WriteString(`\n`)
+			case '\r':
+				sb.WriteString(`\r`)
+			case '\t':
+				sb.WriteString(`\t`)
+			default:
+				sb.WriteString(`\u00`)
+				sb.WriteByte(hexDigits[c>>4])
+				sb.WriteByte(hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			sb.WriteString(s[start:i])
+			sb.WriteString(`�`)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	sb.WriteString(s[start:])
+	sb.WriteByte('"')
+}
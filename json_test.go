@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+type jsonPoint struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func TestWriteObject(t *testing.T) {
+	tests := []struct {
+		name string
+		kv   []interface{}
+		want string
+	}{
+		{"empty", nil, `{}`},
+		{"string", []interface{}{"name", "gopher"}, `{"name":"gopher"}`},
+		{"numbers", []interface{}{"i", -3, "u", uint8(7), "f", 1.5}, `{"i":-3,"u":7,"f":1.5}`},
+		{"bool and nil", []interface{}{"ok", true, "none", nil}, `{"ok":true,"none":null}`},
+		{"escaped", []interface{}{"q\"", "a\\b\n\x01"}, `{"q\"":"a\\b\n\u0001"}`},
+		{"struct", []interface{}{"p", jsonPoint{1, 2}}, `{"p":{"x":1,"y":2}}`},
+		{"slice", []interface{}{"s", []int{1, 2}}, `{"s":[1,2]}`},
+		{"non-string key", []interface{}{1, "a", "b", "c"}, `{"b":"c"}`},
+		{"NaN", []interface{}{"n", math.NaN(), "b", "c"}, `{"b":"c"}`},
+		{"bad value", []interface{}{"ch", make(chan int), "b", "c"}, `{"b":"c"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			WriteObject(sb, tt.kv...)
+			got := sb.String()
+			if got != tt.want {
+				t.Errorf("WriteObject() = %q, want %q", got, tt.want)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Errorf("WriteObject() = %q is not valid JSON", got)
+			}
+		})
+	}
+}
+
+func TestWriteObjectStrict(t *testing.T) {
+	tests := []struct {
+		name string
+		kv   []interface{}
+	}{
+		{"non-string key", []interface{}{1, "a"}},
+		{"NaN", []interface{}{"n", math.NaN()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStrictMode(true, func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("WriteObject() did not panic in strict mode")
+					}
+				}()
+				WriteObject(&strings.Builder{}, tt.kv...)
+			})
+		})
+	}
+}
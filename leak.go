@@ -0,0 +1,123 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"log"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// leakStackDepth is the number of frames recorded for each
+// builder handed out by a pool created WithLeakDetection.
+const leakStackDepth = 16
+
+// leakDetector tracks the builders a pool has handed out
+// and not yet taken back. Builders are keyed by address so
+// that the map does not keep them alive; a builder that is
+// collected while still in the map was leaked.
+type leakDetector struct {
+	mu  sync.Mutex
+	out map[uintptr]struct{}
+}
+
+// WithLeakDetection makes the pool report builders that are
+// handed out by Get and then garbage collected without being
+// released. Get records the caller's stack and sets a
+// finalizer on the builder; if the finalizer runs, the stack
+// is logged to the pool's logger (see WithLogger), or to the
+// standard logger if there is none, pointing at the code
+// that forgot to Release.
+//
+// Recording a stack and setting a finalizer on every Get is
+// slow, so this is for development and tests only. Reports
+// depend on the garbage collector and may arrive late or,
+// at program exit, not at all. Builders from WithAllocator
+// must each be a separate allocation, and released builders
+// the pool did not hand out are dropped rather than parked
+// (see StringPool.Release). WithDebug keeps
+// outstanding builders reachable, so leaks from a pool that
+// also uses it are not reported.
+func WithLeakDetection() Option {
+	return func(bp *StringPool) {
+		bp.leaks = &leakDetector{out: make(map[uintptr]struct{})}
+	}
+}
+
+// leakGet records that sb was handed out.
+func (bp *StringPool) leakGet(sb *strings.Builder) {
+	pcs := make([]uintptr, leakStackDepth)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+
+	d := bp.leaks
+	d.mu.Lock()
+	d.out[uintptr(unsafe.Pointer(sb))] = struct{}{}
+	d.mu.Unlock()
+
+	runtime.SetFinalizer(sb, func(sb *strings.Builder) {
+		d.mu.Lock()
+		delete(d.out, uintptr(unsafe.Pointer(sb)))
+		d.mu.Unlock()
+		bp.reportLeak(sb, pcs)
+	})
+}
+
+// leakRelease records that sb was returned. It reports false
+// for a builder the pool did not hand out, which has no
+// finalizer to clear and should not be parked: a later Get
+// could not set one if it is part of a larger allocation.
+func (bp *StringPool) leakRelease(sb *strings.Builder) bool {
+	d := bp.leaks
+	d.mu.Lock()
+	_, ok := d.out[uintptr(unsafe.Pointer(sb))]
+	delete(d.out, uintptr(unsafe.Pointer(sb)))
+	d.mu.Unlock()
+
+	if ok {
+		runtime.SetFinalizer(sb, nil)
+	}
+	return ok
+}
+
+// reportLeak logs that sb was collected without a Release,
+// with the stack it was handed out from. Frames inside the
+// package are skipped so the report starts at the caller.
+func (bp *StringPool) reportLeak(sb *strings.Builder, pcs []uintptr) {
+	var l Logger = bp.logger
+	if l == nil {
+		l = log.Default()
+	}
+
+	var trace strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		if !isPackageFrame(f) {
+			trace.WriteString("\n\t")
+			trace.WriteString(f.Function)
+			trace.WriteString("\n\t\t")
+			trace.WriteString(f.File)
+			trace.WriteByte(':')
+			trace.WriteString(strconv.Itoa(f.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	l.Printf("stringpool: builder %p was never released; acquired at:%s", sb, trace.String())
+}
+
+// leakPackage is the import path prefix of the functions of
+// this package.
+const leakPackage = "github.com/skeptycal/stringpool."
+
+// isPackageFrame reports whether f is in the package's own
+// non-test code.
+func isPackageFrame(f runtime.Frame) bool {
+	return strings.HasPrefix(f.Function, leakPackage) && !strings.HasSuffix(f.File, "_test.go")
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// collect runs the garbage collector until l has logged n
+// messages or a second has passed, and returns the messages.
+func collect(l *testLogger, n int) []string {
+	deadline := time.Now().Add(time.Second)
+	for {
+		runtime.GC()
+		msgs := l.messages()
+		if len(msgs) >= n || time.Now().After(deadline) {
+			return msgs
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+//go:noinline
+func leakBuilder(p *StringPool) {
+	p.Get().WriteString("forgotten")
+}
+
+func TestWithLeakDetection(t *testing.T) {
+	l := &testLogger{}
+	p := New(WithLeakDetection(), WithLogger(l))
+
+	leakBuilder(p)
+
+	msgs := collect(l, 1)
+	if len(msgs) != 1 {
+		t.Fatalf("logged %d leak reports, want 1: %q", len(msgs), msgs)
+	}
+	for _, want := range []string{"never released", "leakBuilder", "leak_test.go"} {
+		if !strings.Contains(msgs[0], want) {
+			t.Errorf("leak report = %q, want it to mention %q", msgs[0], want)
+		}
+	}
+	if strings.Contains(msgs[0], "(*StringPool).Get") {
+		t.Errorf("leak report = %q, want package frames skipped", msgs[0])
+	}
+}
+
+func TestWithLeakDetectionReleased(t *testing.T) {
+	l := &testLogger{}
+	p := New(WithLeakDetection(), WithLogger(l))
+
+	for i := 0; i < 10; i++ {
+		sb := p.Get()
+		sb.WriteString("returned")
+		p.Release(sb)
+	}
+	// a builder from outside the pool has no finalizer and
+	// is dropped
+	p.Release(&strings.Builder{})
+	if got := p.Stats().Discards; got != 1 {
+		t.Errorf("Stats().Discards after foreign Release() = %d, want 1", got)
+	}
+	p.Drain()
+
+	// a report would arrive soon after collection; give the
+	// finalizers a few cycles to run
+	for i := 0; i < 5; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	if msgs := l.messages(); len(msgs) != 0 {
+		t.Errorf("logged leak reports for released builders: %q", msgs)
+	}
+}
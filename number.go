@@ -0,0 +1,178 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// smallIntCacheSize is the number of small non-negative
+// integers whose string forms are cached, matching the byte
+// range used in the package benchmarks.
+const smallIntCacheSize = 256
+
+// smallInts holds the decimal string forms of 0 through
+// smallIntCacheSize-1.
+var smallInts = func() (a [smallIntCacheSize]string) {
+	for i := range a {
+		a[i] = strconv.Itoa(i)
+	}
+	return a
+}()
+
+// SmallIntString returns the decimal string form of n, like
+// strconv.Itoa. Values from 0 through 255 are served from a
+// cache without allocating, which helps hot loops that
+// format many small numbers; other values are formatted in
+// a pooled builder.
+func SmallIntString(n int) string {
+	if 0 <= n && n < smallIntCacheSize {
+		return smallInts[n]
+	}
+
+	sb := Get()
+	defer Release(sb)
+
+	WriteInt(sb, int64(n))
+	return sb.String()
+}
+
+// WriteInt writes the decimal form of n into sb, as
+// strconv.FormatInt(n, 10) returns it, without allocating an
+// intermediate string; use it instead of fmt.Sprintf("%d")
+// in pooled builders.
+func WriteInt(sb *strings.Builder, n int64) {
+	var buf [20]byte // len("-9223372036854775808")
+	sb.Write(strconv.AppendInt(buf[:0], n, 10))
+}
+
+// WriteUint writes the decimal form of n into sb, as
+// strconv.FormatUint(n, 10) returns it, without allocating
+// an intermediate string.
+func WriteUint(sb *strings.Builder, n uint64) {
+	var buf [20]byte // len("18446744073709551615")
+	sb.Write(strconv.AppendUint(buf[:0], n, 10))
+}
+
+var (
+	smallNumberWords = [...]string{
+		"zero", "one", "two", "three", "four", "five", "six",
+		"seven", "eight", "nine", "ten", "eleven", "twelve",
+		"thirteen", "fourteen", "fifteen", "sixteen",
+		"seventeen", "eighteen", "nineteen",
+	}
+	tensWords = [...]string{
+		"", "", "twenty", "thirty", "forty", "fifty", "sixty",
+		"seventy", "eighty", "ninety",
+	}
+	scaleWords = [...]string{
+		"", "thousand", "million", "billion", "trillion",
+		"quadrillion", "quintillion",
+	}
+)
+
+// WriteNumberWords writes the English words for n into sb,
+// e.g. "one hundred twenty-three" for 123 or "negative
+// forty-two thousand seven" for -42007. Zero is written as
+// "zero". Groups are joined with spaces, without commas or
+// "and", and tens and units are hyphenated.
+func WriteNumberWords(sb *strings.Builder, n int64) {
+	if n == 0 {
+		sb.WriteString(smallNumberWords[0])
+		return
+	}
+
+	// the magnitude of math.MinInt64 does not fit in int64
+	u := uint64(n)
+	if n < 0 {
+		sb.WriteString("negative ")
+		u = -u
+	}
+
+	// split into groups of three digits, least significant first
+	var groups [len(scaleWords)]uint64
+	top := 0
+	for i := range groups {
+		groups[i] = u % 1000
+		if groups[i] != 0 {
+			top = i
+		}
+		u /= 1000
+	}
+
+	first := true
+	for i := top; i >= 0; i-- {
+		if groups[i] == 0 {
+			continue
+		}
+		if !first {
+			sb.WriteByte(' ')
+		}
+		first = false
+
+		writeHundredsWords(sb, int(groups[i]))
+		if i > 0 {
+			sb.WriteByte(' ')
+			sb.WriteString(scaleWords[i])
+		}
+	}
+}
+
+// writeHundredsWords writes the words for 1 <= n <= 999.
+func writeHundredsWords(sb *strings.Builder, n int) {
+	if h := n / 100; h > 0 {
+		sb.WriteString(smallNumberWords[h])
+		sb.WriteString(" hundred")
+		if n %= 100; n == 0 {
+			return
+		}
+		sb.WriteByte(' ')
+	}
+	if n < len(smallNumberWords) {
+		sb.WriteString(smallNumberWords[n])
+		return
+	}
+	sb.WriteString(tensWords[n/10])
+	if n%10 != 0 {
+		sb.WriteByte('-')
+		sb.WriteString(smallNumberWords[n%10])
+	}
+}
+
+// WriteDeterministicFloat writes f into sb in a fixed format
+// that does not depend on the Go version or platform, which
+// makes it suitable for golden-file tests.
+//
+// Finite values are written in exponent notation with the
+// fewest significant digits that uniquely identify the
+// float64 (the shortest round-trip representation), a sign
+// only for negative values, and an exponent of at least two
+// digits:
+//
+//	1       -> 1e+00
+//	-0.5    -> -5e-01
+//	123.456 -> 1.23456e+02
+//	1e-300  -> 1e-300
+//
+// Negative zero is written as -0e+00. Infinities and NaN are
+// written as +Inf, -Inf and NaN.
+func WriteDeterministicFloat(sb *strings.Builder, f float64) {
+	switch {
+	case math.IsNaN(f):
+		sb.WriteString("NaN")
+		return
+	case math.IsInf(f, 1):
+		sb.WriteString("+Inf")
+		return
+	case math.IsInf(f, -1):
+		sb.WriteString("-Inf")
+		return
+	}
+
+	var buf [32]byte
+	sb.Write(strconv.AppendFloat(buf[:0], f, 'e', -1, 64))
+}
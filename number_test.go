@@ -0,0 +1,210 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSmallIntString(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+	}{
+		{"zero", 0},
+		{"one", 1},
+		{"two digits", 42},
+		{"max cached", 255},
+		{"first uncached", 256},
+		{"large", 1234567},
+		{"negative", -1},
+		{"min", math.MinInt64},
+		{"max", math.MaxInt64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := SmallIntString(tt.n), strconv.Itoa(tt.n); got != want {
+				t.Errorf("SmallIntString(%d) = %q, want %q", tt.n, got, want)
+			}
+		})
+	}
+}
+
+func TestSmallIntStringCached(t *testing.T) {
+	for i := 0; i < smallIntCacheSize; i++ {
+		if allocs := testing.AllocsPerRun(10, func() { out = SmallIntString(i) }); allocs != 0 {
+			t.Fatalf("SmallIntString(%d) allocated %v times, want 0", i, allocs)
+		}
+	}
+}
+
+func BenchmarkSmallIntString(b *testing.B) {
+	b.Run("SmallIntString", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for k = 0; k < 255; k++ {
+				out = SmallIntString(int(k))
+			}
+		}
+	})
+	b.Run("strconv.Itoa", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for k = 0; k < 255; k++ {
+				out = strconv.Itoa(int(k))
+			}
+		}
+	})
+}
+
+func TestWriteInt(t *testing.T) {
+	tests := []int64{0, 1, -1, 9, 10, -10, 255, 256, 1 << 31, -1 << 31, math.MaxInt64, math.MinInt64, math.MinInt64 + 1}
+	for _, n := range tests {
+		sb := &strings.Builder{}
+		sb.WriteByte('[')
+		WriteInt(sb, n)
+		sb.WriteByte(']')
+		if got, want := sb.String(), "["+strconv.FormatInt(n, 10)+"]"; got != want {
+			t.Errorf("WriteInt(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestWriteUint(t *testing.T) {
+	tests := []uint64{0, 1, 9, 10, 255, 256, 1 << 32, math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64}
+	for _, n := range tests {
+		sb := &strings.Builder{}
+		sb.WriteByte('[')
+		WriteUint(sb, n)
+		sb.WriteByte(']')
+		if got, want := sb.String(), "["+strconv.FormatUint(n, 10)+"]"; got != want {
+			t.Errorf("WriteUint(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+// BenchmarkWriteInt writes into a large builder that is only
+// replaced once full, so that allocs/op shows the formatting
+// alone: zero for WriteInt, one string per number otherwise.
+func BenchmarkWriteInt(b *testing.B) {
+	const size = 1 << 20
+	sb := &strings.Builder{}
+
+	b.Run("WriteInt", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if sb.Cap()-sb.Len() < 40 {
+				sb.Reset()
+				sb.Grow(size)
+			}
+			WriteInt(sb, math.MinInt64)
+			WriteUint(sb, math.MaxUint64)
+		}
+	})
+	b.Run("WriteString(FormatInt)", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if sb.Cap()-sb.Len() < 40 {
+				sb.Reset()
+				sb.Grow(size)
+			}
+			sb.WriteString(strconv.FormatInt(math.MinInt64, 10))
+			sb.WriteString(strconv.FormatUint(math.MaxUint64, 10))
+		}
+	})
+}
+
+func TestWriteIntAllocs(t *testing.T) {
+	sb := &strings.Builder{}
+	sb.Grow(64)
+	allocs := testing.AllocsPerRun(100, func() {
+		sb.Reset()
+		sb.Grow(64)
+		WriteInt(sb, math.MinInt64)
+		WriteUint(sb, math.MaxUint64)
+	})
+	// the only allocation is the Grow after Reset
+	if allocs > 1 {
+		t.Errorf("WriteInt() and WriteUint() allocated %v times, want <= 1", allocs)
+	}
+}
+
+func TestWriteNumberWords(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "zero"},
+		{7, "seven"},
+		{10, "ten"},
+		{13, "thirteen"},
+		{19, "nineteen"},
+		{20, "twenty"},
+		{42, "forty-two"},
+		{99, "ninety-nine"},
+		{100, "one hundred"},
+		{101, "one hundred one"},
+		{115, "one hundred fifteen"},
+		{123, "one hundred twenty-three"},
+		{999, "nine hundred ninety-nine"},
+		{1000, "one thousand"},
+		{1001, "one thousand one"},
+		{12345, "twelve thousand three hundred forty-five"},
+		{1000000, "one million"},
+		{1002003, "one million two thousand three"},
+		{2000000010, "two billion ten"},
+		{-1, "negative one"},
+		{-42007, "negative forty-two thousand seven"},
+		{math.MaxInt64, "nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion " +
+			"thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred seven"},
+		{math.MinInt64, "negative nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion " +
+			"thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred eight"},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.FormatInt(tt.n, 10), func(t *testing.T) {
+			sb := &strings.Builder{}
+			WriteNumberWords(sb, tt.n)
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteNumberWords(%d) = %q, want %q", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteDeterministicFloat(t *testing.T) {
+	tests := []struct {
+		name string
+		f    float64
+		want string
+	}{
+		{"zero", 0, "0e+00"},
+		{"negative zero", math.Copysign(0, -1), "-0e+00"},
+		{"one", 1, "1e+00"},
+		{"half", -0.5, "-5e-01"},
+		{"decimal", 123.456, "1.23456e+02"},
+		{"tenth", 0.1, "1e-01"},
+		{"third", 1.0 / 3, "3.333333333333333e-01"},
+		{"integer", 1234567890, "1.23456789e+09"},
+		{"very large", 1e300, "1e+300"},
+		{"very small", 1e-300, "1e-300"},
+		{"max", math.MaxFloat64, "1.7976931348623157e+308"},
+		{"smallest", math.SmallestNonzeroFloat64, "5e-324"},
+		{"+Inf", math.Inf(1), "+Inf"},
+		{"-Inf", math.Inf(-1), "-Inf"},
+		{"NaN", math.NaN(), "NaN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			WriteDeterministicFloat(sb, tt.f)
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteDeterministicFloat(%v) = %q, want %q", tt.f, got, tt.want)
+			}
+		})
+	}
+}
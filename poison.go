@@ -0,0 +1,78 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+//go:build stringpool_poison
+// +build stringpool_poison
+
+package stringpool
+
+import (
+	"strings"
+	"unsafe"
+)
+
+const (
+	// poisonByte fills released builders. It is not valid
+	// UTF-8 on its own, so poisoned text stands out.
+	poisonByte = 0xDB
+
+	// poisonMinLen is the least number of poison bytes a
+	// released builder holds, so that it never reads as empty.
+	poisonMinLen = 8
+)
+
+// builderHeader mirrors the layout of strings.Builder so that
+// its buffer can be reached.
+type builderHeader struct {
+	addr *strings.Builder
+	buf  []byte
+}
+
+// builderBuf returns a pointer to the buffer of sb.
+func builderBuf(sb *strings.Builder) *[]byte {
+	return &(*builderHeader)(unsafe.Pointer(sb)).buf
+}
+
+// WithPoisonOnRelease makes Release fill each builder with a
+// poison byte pattern instead of leaving it empty. Code that
+// keeps using a builder after releasing it then reads or
+// appends to garbage, which shows up loudly in tests instead
+// of passing silently. Get empties the builder again before
+// handing it out.
+//
+// The option only takes effect in builds with the
+// stringpool_poison tag:
+//
+//	go test -tags stringpool_poison ./...
+//
+// Without the tag it does nothing, so it may be left in test
+// setup code. It costs an allocation per Release and is meant
+// for tests only.
+//
+// The poison goes into a fresh buffer rather than over the
+// old one: strings returned by String before Release share
+// the old buffer and remain valid, as they are in a normal
+// build.
+func WithPoisonOnRelease() Option {
+	return func(bp *StringPool) {
+		bp.poison = true
+	}
+}
+
+// poisonBuilder replaces the contents of sb with poison bytes,
+// at least as many as it held.
+func poisonBuilder(sb *strings.Builder) {
+	n := sb.Len()
+	if n < poisonMinLen {
+		n = poisonMinLen
+	}
+	sb.Reset()
+	sb.Grow(n)
+
+	buf := builderBuf(sb)
+	*buf = (*buf)[:n]
+	for i := range *buf {
+		(*buf)[i] = poisonByte
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+//go:build !stringpool_poison
+// +build !stringpool_poison
+
+package stringpool
+
+import "strings"
+
+// WithPoisonOnRelease makes Release fill each builder with a
+// poison byte pattern to expose use after release. It only
+// takes effect in builds with the stringpool_poison tag; in
+// this build it does nothing.
+func WithPoisonOnRelease() Option {
+	return func(bp *StringPool) {}
+}
+
+// poisonBuilder is never called without the stringpool_poison
+// tag, since no pool can have poisoning enabled.
+func poisonBuilder(sb *strings.Builder) {
+	sb.Reset()
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+//go:build stringpool_poison
+// +build stringpool_poison
+
+package stringpool
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithPoisonOnRelease(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantLen int
+	}{
+		{"empty", "", poisonMinLen},
+		{"short", "secret", poisonMinLen},
+		{"long", strings.Repeat("secret ", 10), 70},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(WithPoisonOnRelease())
+
+			sb := p.Get()
+			sb.WriteString(tt.content)
+			s := sb.String()
+			p.Release(sb)
+
+			// use after release: read the buffer the way an
+			// unsafe zero-copy view of the builder would
+			buf := *builderBuf(sb)
+			if len(buf) != tt.wantLen {
+				t.Errorf("released buffer len = %d, want %d", len(buf), tt.wantLen)
+			}
+			for i, c := range buf {
+				if c != poisonByte {
+					t.Fatalf("released buffer[%d] = %#x, want poison %#x", i, c, poisonByte)
+				}
+			}
+			if got, want := sb.String(), strings.Repeat("\xdb", tt.wantLen); got != want {
+				t.Errorf("released String() = %q, want %q", got, want)
+			}
+
+			// strings obtained before Release are untouched
+			if s != tt.content {
+				t.Errorf("String() before Release = %q, want %q", s, tt.content)
+			}
+
+			if got := p.Get().Len(); got != 0 {
+				t.Errorf("Get().Len() after poisoned Release = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestWithPoisonOnReleaseMaxCap(t *testing.T) {
+	p := New(WithPoisonOnRelease(), WithMaxRetainedCap(64))
+
+	sb := p.Get()
+	sb.Grow(1024)
+	sb.WriteString("x")
+	p.Release(sb)
+
+	if got := sb.String(); got != strings.Repeat("\xdb", poisonMinLen) {
+		t.Errorf("dropped builder String() = %q, want poison", got)
+	}
+}
+
+func TestWithoutPoisonOnRelease(t *testing.T) {
+	p := New()
+
+	sb := p.Get()
+	sb.WriteString("secret")
+	p.Release(sb)
+
+	if got := sb.Len(); got != 0 {
+		t.Errorf("released Len() without poison = %d, want 0", got)
+	}
+}
+
+func TestWithPoisonOnReleaseConfig(t *testing.T) {
+	if got := New(WithPoisonOnRelease()).Config().ResetPolicy; got != ResetPolicyPoison {
+		t.Errorf("Config().ResetPolicy = %q, want %q", got, ResetPolicyPoison)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "sync"
+
+// Pool is a generic pool of *T with the same semantics as
+// StringPool: Get returns a reset value, ready to use, and
+// Release resets a value and parks it for reuse. It suits
+// types such as bytes.Buffer:
+//
+//	buffers := stringpool.NewPool(
+//		func() *bytes.Buffer { return new(bytes.Buffer) },
+//		(*bytes.Buffer).Reset,
+//	)
+//	buf := buffers.Get()
+//	defer buffers.Release(buf)
+//
+// A Pool[strings.Builder] satisfies Pooler, but lacks the
+// statistics and options of a StringPool.
+//
+// The zero value is ready to use, as if made by NewPool with
+// nil funcs. A Pool is safe for use by multiple goroutines
+// simultaneously and must not be copied after first use.
+type Pool[T any] struct {
+	p     sync.Pool
+	reset func(*T) // nil to set released values to zero
+}
+
+// NewPool returns a Pool that allocates values with newFn and
+// clears them with resetFn on Release. If newFn is nil, new(T)
+// is used; if resetFn is nil, released values are set to the
+// zero value of T.
+func NewPool[T any](newFn func() *T, resetFn func(*T)) *Pool[T] {
+	p := &Pool[T]{reset: resetFn}
+	if newFn != nil {
+		p.p.New = func() any { return newFn() }
+	}
+	return p
+}
+
+// Get returns a value from the pool, allocating one if none
+// is available.
+func (p *Pool[T]) Get() *T {
+	if v := p.p.Get(); v != nil {
+		return v.(*T)
+	}
+	return new(T)
+}
+
+// Release resets v and returns it to the pool. v must not be
+// used afterwards. Releasing nil is a no-op.
+func (p *Pool[T]) Release(v *T) {
+	if v == nil {
+		return
+	}
+	if p.reset != nil {
+		p.reset(v)
+	} else {
+		var zero T
+		*v = zero
+	}
+	p.p.Put(v)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+var _ Pooler = (*Pool[strings.Builder])(nil)
+
+func TestPoolBytesBuffer(t *testing.T) {
+	news := 0
+	p := NewPool(func() *bytes.Buffer {
+		news++
+		return new(bytes.Buffer)
+	}, (*bytes.Buffer).Reset)
+
+	for i := 0; i < 3; i++ {
+		buf := p.Get()
+		if buf.Len() != 0 {
+			t.Fatalf("round %d: Get() returned a buffer holding %q", i, buf.String())
+		}
+		buf.WriteString("hello, ")
+		buf.WriteString("world")
+		if got, want := buf.String(), "hello, world"; got != want {
+			t.Errorf("round %d: buffer = %q, want %q", i, got, want)
+		}
+		p.Release(buf)
+		if buf.Len() != 0 {
+			t.Errorf("round %d: Release() left Len() = %d, want 0", i, buf.Len())
+		}
+	}
+	if news < 1 {
+		t.Errorf("newFn called %d times, want at least 1", news)
+	}
+}
+
+func TestPoolDefaults(t *testing.T) {
+	type counter struct{ n int }
+	tests := []struct {
+		name string
+		p    *Pool[counter]
+	}{
+		{"nil funcs", NewPool[counter](nil, nil)},
+		{"zero value", &Pool[counter]{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.p.Get()
+			if c.n != 0 {
+				t.Errorf("Get() = %+v, want zero value", *c)
+			}
+			c.n = 42
+			tt.p.Release(c)
+			if c.n != 0 {
+				t.Errorf("Release() left %+v, want zero value", *c)
+			}
+
+			tt.p.Release(nil)
+		})
+	}
+}
+
+func TestPoolStringsBuilder(t *testing.T) {
+	var p Pooler = NewPool(nil, (*strings.Builder).Reset)
+
+	sb := p.Get()
+	sb.WriteString("hello")
+	p.Release(sb)
+
+	if got := p.Get().Len(); got != 0 {
+		t.Errorf("Get().Len() after Release = %d, want 0", got)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// Pooler is the interface of a source of strings.Builder
+// objects. It is implemented by *StringPool, by NoPool and by
+// the wrapper returned from WithMetrics, so that code can be
+// written against any of them.
+type Pooler interface {
+	// Get returns an empty strings.Builder.
+	Get() *strings.Builder
+
+	// Release returns a builder obtained from Get. The
+	// builder must not be used afterwards.
+	Release(sb *strings.Builder)
+}
+
+var (
+	_ Pooler = (*StringPool)(nil)
+	_ Pooler = NoPool{}
+	_ Pooler = (*MeteredPool)(nil)
+)
+
+// NoPool is a Pooler that does not pool: Get allocates a new
+// builder every time and Release discards it. It is the
+// baseline to compare a StringPool against in benchmarks.
+type NoPool struct{}
+
+// Get returns a newly allocated strings.Builder.
+func (NoPool) Get() *strings.Builder {
+	return new(strings.Builder)
+}
+
+// Release does nothing; the builder is left to the garbage
+// collector.
+func (NoPool) Release(sb *strings.Builder) {}
+
+// Metrics is a snapshot of the counters kept by a MeteredPool.
+type Metrics struct {
+	// Gets is the number of builders handed out by Get.
+	Gets uint64
+
+	// Releases is the number of builders returned by Release.
+	Releases uint64
+}
+
+// MeteredPool wraps a Pooler and counts the Gets and Releases
+// made through it. It is safe for concurrent use if the
+// wrapped Pooler is.
+type MeteredPool struct {
+	// counters are accessed atomically and are kept first
+	// in the struct for 64-bit alignment.
+	gets     uint64
+	releases uint64
+
+	p Pooler
+}
+
+// WithMetrics returns a Pooler that passes calls through to p
+// and counts them. The counts are read with Metrics:
+//
+//	mp := stringpool.WithMetrics(stringpool.New())
+//	sb := mp.Get()
+//	mp.Release(sb)
+//	m := mp.Metrics() // m.Gets == 1, m.Releases == 1
+//
+// Only calls made through the returned wrapper are counted.
+func WithMetrics(p Pooler) *MeteredPool {
+	return &MeteredPool{p: p}
+}
+
+// Get returns a builder from the wrapped Pooler.
+func (m *MeteredPool) Get() *strings.Builder {
+	atomic.AddUint64(&m.gets, 1)
+	return m.p.Get()
+}
+
+// Release returns sb to the wrapped Pooler.
+func (m *MeteredPool) Release(sb *strings.Builder) {
+	atomic.AddUint64(&m.releases, 1)
+	m.p.Release(sb)
+}
+
+// Metrics returns a snapshot of the pool's counters.
+func (m *MeteredPool) Metrics() Metrics {
+	return Metrics{
+		Gets:     atomic.LoadUint64(&m.gets),
+		Releases: atomic.LoadUint64(&m.releases),
+	}
+}
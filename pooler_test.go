@@ -0,0 +1,61 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestWithMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		pool     Pooler
+		gets     int
+		releases int
+	}{
+		{"none", New(), 0, 0},
+		{"pool", New(), 5, 5},
+		{"pool outstanding", New(), 5, 3},
+		{"no pool", NoPool{}, 4, 4},
+		{"nested", WithMetrics(New()), 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mp := WithMetrics(tt.pool)
+
+			var wg sync.WaitGroup
+			for i := 0; i < tt.gets; i++ {
+				wg.Add(1)
+				go func(release bool) {
+					defer wg.Done()
+					sb := mp.Get()
+					sb.WriteString("hello")
+					if release {
+						mp.Release(sb)
+					}
+				}(i < tt.releases)
+			}
+			wg.Wait()
+
+			want := Metrics{Gets: uint64(tt.gets), Releases: uint64(tt.releases)}
+			if got := mp.Metrics(); got != want {
+				t.Errorf("Metrics() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestWithMetricsPassThrough(t *testing.T) {
+	p := New()
+	mp := WithMetrics(p)
+
+	sb := mp.Get()
+	mp.Release(sb)
+
+	if got := p.Stats(); got.Gets != 1 || got.Releases != 1 {
+		t.Errorf("wrapped pool Stats() = %+v, want 1 Get and 1 Release", got)
+	}
+}
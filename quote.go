@@ -0,0 +1,88 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// WriteUnquoted writes the value of the Go string literal s
+// into sb, as strconv.Unquote would return it, without
+// allocating an intermediate string. s may be double quoted,
+// back quoted or single quoted (a rune literal).
+//
+// If s is not a valid literal, WriteUnquoted returns
+// strconv.ErrSyntax and nothing is written to sb.
+func WriteUnquoted(sb *strings.Builder, s string) error {
+	n := len(s)
+	if n < 2 || s[0] != s[n-1] {
+		return strconv.ErrSyntax
+	}
+	quote, body := s[0], s[1:n-1]
+
+	switch quote {
+	case '`':
+		if strings.IndexByte(body, '`') >= 0 {
+			return strconv.ErrSyntax
+		}
+		// carriage returns are discarded from raw literals
+		if strings.IndexByte(body, '\r') < 0 {
+			sb.WriteString(body)
+			return nil
+		}
+		sb.Grow(len(body))
+		for i := 0; i < len(body); i++ {
+			if body[i] != '\r' {
+				sb.WriteByte(body[i])
+			}
+		}
+		return nil
+	case '"', '\'':
+	default:
+		return strconv.ErrSyntax
+	}
+
+	if strings.IndexByte(body, '\n') >= 0 {
+		return strconv.ErrSyntax
+	}
+	if quote == '"' && strings.IndexByte(body, '\\') < 0 && strings.IndexByte(body, '"') < 0 && utf8.ValidString(body) {
+		sb.WriteString(body)
+		return nil
+	}
+
+	// validate first so that nothing is written on error
+	if err := unquoteBody(nil, body, quote); err != nil {
+		return err
+	}
+	return unquoteBody(sb, body, quote)
+}
+
+// unquoteBody decodes the escaped body of a quoted literal,
+// writing the result into sb unless sb is nil. A single
+// quoted body must hold at most one character; like
+// strconv.Unquote, an empty rune literal is accepted.
+func unquoteBody(sb *strings.Builder, body string, quote byte) error {
+	for len(body) > 0 {
+		r, multibyte, tail, err := strconv.UnquoteChar(body, quote)
+		if err != nil {
+			return err
+		}
+		body = tail
+		if quote == '\'' && len(body) > 0 {
+			return strconv.ErrSyntax
+		}
+		if sb == nil {
+			continue
+		}
+		if r < utf8.RuneSelf || !multibyte {
+			sb.WriteByte(byte(r))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return nil
+}
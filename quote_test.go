@@ -0,0 +1,103 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteUnquoted(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"empty", `""`, "", false},
+		{"plain", `"hello"`, "hello", false},
+		{"simple escapes", `"a\tb\nc\\d\"e"`, "a\tb\nc\\d\"e", false},
+		{"control escapes", `"\a\b\f\n\r\t\v"`, "\a\b\f\n\r\t\v", false},
+		{"rune escape in string", `"\'"`, "", true},
+		{"octal and hex", `"\101\x42"`, "AB", false},
+		{"high byte", `"\xff"`, "\xff", false},
+		{"unicode escapes", `"\u00e9\U0001F600"`, "é😀", false},
+		{"literal unicode", `"héllo, 世界"`, "héllo, 世界", false},
+		{"raw", "`a\\n\"b`", `a\n"b`, false},
+		{"raw drops carriage return", "`a\r\nb`", "a\nb", false},
+		{"rune", `'x'`, "x", false},
+		{"rune escape", `'\n'`, "\n", false},
+		{"rune unicode", `'世'`, "世", false},
+		{"rune quote", `'\''`, "'", false},
+		{"empty rune", `''`, "", false}, // accepted by strconv.Unquote
+
+		{"too short", `"`, "", true},
+		{"unquoted", `hello`, "", true},
+		{"mismatched quotes", `"hello'`, "", true},
+		{"unknown escape", `"\q"`, "", true},
+		{"truncated escape", `"\x4"`, "", true},
+		{"bad unicode escape", `"\u12"`, "", true},
+		{"surrogate", `"\ud800"`, "", true},
+		{"trailing backslash", `"abc\"`, "", true},
+		{"inner quote", `"a"b"`, "", true},
+		{"newline", "\"a\nb\"", "", true},
+		{"raw with backquote", "`a`b`", "", true},
+		{"two runes", `'ab'`, "", true},
+		{"escaped double quote in rune", `'\"'`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := Get()
+			defer Release(sb)
+
+			sb.WriteString("prefix:")
+			err := WriteUnquoted(sb, tt.in)
+
+			want, wantErr := strconv.Unquote(tt.in)
+			if (err != nil) != (wantErr != nil) {
+				t.Fatalf("WriteUnquoted(%s) error = %v, strconv.Unquote error = %v", tt.in, err, wantErr)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WriteUnquoted(%s) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if err != nil {
+				if got := sb.String(); got != "prefix:" {
+					t.Errorf("WriteUnquoted(%s) wrote %q on error, want nothing", tt.in, strings.TrimPrefix(got, "prefix:"))
+				}
+				return
+			}
+			if got := strings.TrimPrefix(sb.String(), "prefix:"); got != want || got != tt.want {
+				t.Errorf("WriteUnquoted(%s) = %q, want %q (strconv.Unquote %q)", tt.in, got, tt.want, want)
+			}
+		})
+	}
+}
+
+func BenchmarkWriteUnquoted(b *testing.B) {
+	const in = `"name:\t\"stringpool\"\nversion:é"`
+
+	b.Run("WriteUnquoted", func(b *testing.B) {
+		b.ReportAllocs()
+		sb := Get()
+		defer Release(sb)
+		for i := 0; i < b.N; i++ {
+			sb.Reset()
+			sb.Grow(len(in))
+			_ = WriteUnquoted(sb, in)
+		}
+	})
+	b.Run("strconv.Unquote", func(b *testing.B) {
+		b.ReportAllocs()
+		sb := Get()
+		defer Release(sb)
+		for i := 0; i < b.N; i++ {
+			sb.Reset()
+			sb.Grow(len(in))
+			s, _ := strconv.Unquote(in)
+			sb.WriteString(s)
+		}
+	})
+}
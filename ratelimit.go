@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned by TryGet when the pool's rate
+// limit (see WithRateLimit) has been reached.
+var ErrRateLimited = errors.New("stringpool: rate limit exceeded")
+
+// rateLimiter is a token bucket holding up to one second's
+// worth of tokens. The token count goes negative while Gets
+// are waiting, which queues them in arrival order.
+type rateLimiter struct {
+	mu        sync.Mutex
+	perSecond int           // as configured
+	every     time.Duration // time to earn one token, at least 1ns
+	burst     float64
+	tokens    float64
+	last      time.Time
+}
+
+// WithRateLimit limits the pool to perSecond Gets per second,
+// to protect downstream systems from build storms in shared
+// environments. Up to perSecond Gets may be made at once
+// after a quiet period; beyond that, Get blocks until the
+// rate allows it, while TryGet returns ErrRateLimited.
+//
+// A value of zero or less means no limit, the default.
+// Rates above one Get per nanosecond are enforced as that
+// rate, though Config reports the rate as given.
+func WithRateLimit(perSecond int) Option {
+	return func(bp *StringPool) {
+		if perSecond <= 0 {
+			bp.limiter = nil
+			return
+		}
+		bp.limiter = &rateLimiter{
+			perSecond: perSecond,
+			every:     max(time.Second/time.Duration(perSecond), 1),
+			burst:     float64(perSecond),
+			tokens:    float64(perSecond),
+			last:      time.Now(),
+		}
+	}
+}
+
+// TryGet is like Get, but returns ErrRateLimited instead of
+// blocking when the pool's rate limit has been reached.
+func (bp *StringPool) TryGet() (*strings.Builder, error) {
+	if bp.limiter != nil && !bp.limiter.take(false) {
+		return nil, ErrRateLimited
+	}
+	return bp.get(), nil
+}
+
+// wait takes a token, sleeping until one is available.
+func (l *rateLimiter) wait() {
+	l.take(true)
+}
+
+// take takes a token if one is available and reports whether
+// it did. If block is set, a token is always taken, sleeping
+// for as long as it takes to earn it.
+func (l *rateLimiter) take(block bool) bool {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += float64(now.Sub(l.last)) / float64(l.every)
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return true
+	}
+	if !block {
+		l.mu.Unlock()
+		return false
+	}
+	d := time.Duration((1 - l.tokens) * float64(l.every))
+	l.tokens--
+	l.mu.Unlock()
+
+	time.Sleep(d)
+	return true
+}
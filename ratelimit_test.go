@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		perSecond int
+		gets      int
+		minTime   time.Duration
+	}{
+		// 100 Gets beyond the burst of 200 must wait 0.5s
+		{"throttled", 200, 300, 400 * time.Millisecond},
+		{"within burst", 200, 200, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(WithRateLimit(tt.perSecond))
+
+			start := time.Now()
+			for i := 0; i < tt.gets; i++ {
+				p.Release(p.Get())
+			}
+			elapsed := time.Since(start)
+
+			if elapsed < tt.minTime {
+				t.Errorf("%d Gets at %d/s took %v, want at least %v", tt.gets, tt.perSecond, elapsed, tt.minTime)
+			}
+			if tt.minTime == 0 && elapsed > 100*time.Millisecond {
+				t.Errorf("%d Gets within the burst took %v, want no waiting", tt.gets, elapsed)
+			}
+		})
+	}
+}
+
+func TestWithRateLimitDisabled(t *testing.T) {
+	tests := []struct {
+		name string
+		pool *StringPool
+	}{
+		{"default", New()},
+		{"zero", New(WithRateLimit(0))},
+		{"negative", New(WithRateLimit(-1))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 10000; i++ {
+				sb, err := tt.pool.TryGet()
+				if err != nil {
+					t.Fatalf("TryGet() #%d error = %v, want nil", i, err)
+				}
+				tt.pool.Release(sb)
+			}
+		})
+	}
+}
+
+func TestWithRateLimitHuge(t *testing.T) {
+	for _, rate := range []int{999_999_999, 1_000_000_000, 2_000_000_000, math.MaxInt} {
+		p := New(WithRateLimit(rate))
+		if got := p.Config().RateLimit; got != rate {
+			t.Errorf("WithRateLimit(%d) Config().RateLimit = %d", rate, got)
+		}
+		if got := p.Clone().Config().RateLimit; got != rate {
+			t.Errorf("WithRateLimit(%d) Clone().Config().RateLimit = %d", rate, got)
+		}
+		sb, err := p.TryGet()
+		if err != nil {
+			t.Fatalf("WithRateLimit(%d) TryGet() error = %v", rate, err)
+		}
+		p.Release(sb)
+	}
+}
+
+func TestTryGet(t *testing.T) {
+	p := New(WithRateLimit(10))
+
+	for i := 0; i < 10; i++ {
+		sb, err := p.TryGet()
+		if err != nil {
+			t.Fatalf("TryGet() #%d within burst error = %v, want nil", i, err)
+		}
+		p.Release(sb)
+	}
+
+	sb, err := p.TryGet()
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("TryGet() beyond burst error = %v, want %v", err, ErrRateLimited)
+	}
+	if sb != nil {
+		t.Errorf("TryGet() beyond burst returned a builder")
+	}
+	if got := p.Stats().Gets; got != 10 {
+		t.Errorf("Stats().Gets = %d, want 10", got)
+	}
+}
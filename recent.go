@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"sync"
+)
+
+// RecentBuilds builds strings in pooled builders and keeps
+// the last n of them in a ring, evicting the oldest as new
+// ones are added. It is a building block for "recent N
+// lines" features such as a tail of log output.
+//
+// A RecentBuilds is safe for use by multiple goroutines
+// simultaneously.
+type RecentBuilds struct {
+	mu   sync.Mutex
+	ring []string
+	next int // index of the slot Add fills next
+	full bool
+	pool *StringPool
+}
+
+// NewRecentBuilds returns a RecentBuilds that keeps the last
+// n strings, drawing its builders from the global pool. An n
+// of less than one is misuse and is handled according to
+// SetStrictMode by keeping nothing.
+func NewRecentBuilds(n int) *RecentBuilds {
+	return Default().NewRecentBuilds(n)
+}
+
+// NewRecentBuilds returns a RecentBuilds that keeps the last
+// n strings, drawing its builders from the pool.
+func (bp *StringPool) NewRecentBuilds(n int) *RecentBuilds {
+	if n < 1 {
+		misuse("NewRecentBuilds: window size must be > 0, got %d", n)
+		n = 0
+	}
+	return &RecentBuilds{ring: make([]string, n), pool: bp}
+}
+
+// Add calls fn to build a string and adds it to the window,
+// evicting the oldest string if the window is full.
+func (r *RecentBuilds) Add(fn func(sb *strings.Builder)) {
+	sb := r.pool.Get()
+	defer r.pool.Release(sb)
+
+	fn(sb)
+	// copy the result so that the window does not keep the
+	// builder's whole backing array alive
+	s := strings.Clone(sb.String())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.ring) == 0 {
+		return
+	}
+	r.ring[r.next] = s
+	r.next++
+	if r.next == len(r.ring) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// Len returns the number of strings in the window.
+func (r *RecentBuilds) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.full {
+		return len(r.ring)
+	}
+	return r.next
+}
+
+// Snapshot returns a copy of the strings in the window,
+// oldest first.
+func (r *RecentBuilds) Snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]string(nil), r.ring[:r.next]...)
+	}
+	out := make([]string, 0, len(r.ring))
+	out = append(out, r.ring[r.next:]...)
+	return append(out, r.ring[:r.next]...)
+}
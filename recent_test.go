@@ -0,0 +1,95 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecentBuilds(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		adds int
+		want []string
+	}{
+		{"empty", 3, 0, nil},
+		{"partial", 3, 2, []string{"line 0", "line 1"}},
+		{"exactly full", 3, 3, []string{"line 0", "line 1", "line 2"}},
+		{"evicts oldest", 3, 5, []string{"line 2", "line 3", "line 4"}},
+		{"wraps twice", 2, 7, []string{"line 5", "line 6"}},
+		{"size one", 1, 4, []string{"line 3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRecentBuilds(tt.size)
+			for i := 0; i < tt.adds; i++ {
+				r.Add(func(sb *strings.Builder) {
+					sb.WriteString("line ")
+					sb.WriteString(SmallIntString(i))
+				})
+			}
+
+			got := r.Snapshot()
+			if len(got) == 0 && len(tt.want) == 0 {
+				got = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Snapshot() = %q, want %q", got, tt.want)
+			}
+			if n := r.Len(); n != len(tt.want) {
+				t.Errorf("Len() = %d, want %d", n, len(tt.want))
+			}
+			if n := r.Len(); n > tt.size {
+				t.Errorf("Len() = %d exceeds window size %d", n, tt.size)
+			}
+		})
+	}
+}
+
+func TestRecentBuildsSnapshotIsCopy(t *testing.T) {
+	r := NewRecentBuilds(2)
+	r.Add(func(sb *strings.Builder) { sb.WriteString("a") })
+
+	snap := r.Snapshot()
+	snap[0] = "changed"
+	r.Add(func(sb *strings.Builder) { sb.WriteString("b") })
+
+	if got, want := r.Snapshot(), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Snapshot() = %q, want %q", got, want)
+	}
+}
+
+func TestRecentBuildsMisuse(t *testing.T) {
+	r := NewRecentBuilds(0)
+	r.Add(func(sb *strings.Builder) { sb.WriteString("a") })
+	if got := r.Len(); got != 0 {
+		t.Errorf("Len() of zero size window = %d, want 0", got)
+	}
+
+	withStrictMode(true, func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("NewRecentBuilds(0) did not panic in strict mode")
+			}
+		}()
+		NewRecentBuilds(0)
+	})
+}
+
+func TestRecentBuildsCopiesResult(t *testing.T) {
+	r := New().NewRecentBuilds(1)
+	var built string
+	r.Add(func(sb *strings.Builder) {
+		sb.Grow(4096)
+		sb.WriteString("line")
+		built = sb.String()
+	})
+	if got := r.Snapshot()[0]; got != "line" || sameBacking(got, built) {
+		t.Errorf("RecentBuilds kept %q in the builder's backing array", got)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// WriteStruct writes the exported fields of the struct v
+// into sb as space separated FieldName=value pairs, e.g.
+//
+//	Name=gopher Age=12 Addr={City=Denver Zip=80202}
+//
+// It is a quick, pooled alternative to fmt.Sprintf("%+v", v)
+// for debug output. v may be a struct or a pointer to one.
+//
+// Nested and embedded structs are expanded one level deep,
+// enclosed in braces, with embedded structs named by their
+// type as with %+v; structs nested more deeply are written
+// as with %+v. Nil pointers, maps, slices and interfaces are
+// written as <nil>. If v is not a struct, it is written as
+// with fmt.Fprint.
+func WriteStruct(sb *strings.Builder, v interface{}) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			sb.WriteString("<nil>")
+			return
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		fmt.Fprint(sb, v)
+		return
+	}
+	writeStructFields(sb, rv, 0)
+}
+
+// writeStructFields writes the exported fields of the
+// struct rv. depth is the current nesting level.
+func writeStructFields(sb *strings.Builder, rv reflect.Value, depth int) {
+	rt := rv.Type()
+	first := true
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" && !(f.Anonymous && f.Type.Kind() == reflect.Struct) {
+			// unexported, other than an embedded struct
+			// whose exported fields are promoted
+			continue
+		}
+		if !first {
+			sb.WriteByte(' ')
+		}
+		first = false
+
+		sb.WriteString(f.Name)
+		sb.WriteByte('=')
+		writeStructValue(sb, rv.Field(i), depth)
+	}
+}
+
+// writeStructValue writes a single field value.
+func writeStructValue(sb *strings.Builder, v reflect.Value, depth int) {
+	var buf [32]byte
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			sb.WriteString("<nil>")
+			return
+		}
+	}
+	if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Struct {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		sb.WriteString(v.String())
+	case reflect.Bool:
+		sb.Write(strconv.AppendBool(buf[:0], v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sb.Write(strconv.AppendInt(buf[:0], v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		sb.Write(strconv.AppendUint(buf[:0], v.Uint(), 10))
+	case reflect.Float32:
+		sb.Write(strconv.AppendFloat(buf[:0], v.Float(), 'g', -1, 32))
+	case reflect.Float64:
+		sb.Write(strconv.AppendFloat(buf[:0], v.Float(), 'g', -1, 64))
+	case reflect.Struct:
+		if depth > 0 {
+			fmt.Fprintf(sb, "%+v", v)
+			return
+		}
+		sb.WriteByte('{')
+		writeStructFields(sb, v, depth+1)
+		sb.WriteByte('}')
+	default:
+		// fmt prints the value held by a reflect.Value, even
+		// one reached through an unexported embedded struct
+		fmt.Fprint(sb, v)
+	}
+}
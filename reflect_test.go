@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"testing"
+)
+
+type structBase struct {
+	ID int
+}
+
+type structInner struct {
+	City string
+	Deep structBase
+}
+
+type structMixed struct {
+	structBase
+	Name    string
+	Age     uint8
+	Score   float64
+	Active  bool
+	Tags    []string
+	Inner   structInner
+	Ptr     *structInner
+	NilPtr  *structInner
+	Any     interface{}
+	private string
+}
+
+func TestWriteStruct(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		{"empty", struct{}{}, ""},
+		{"simple", struct {
+			A int
+			B string
+		}{-1, "x y"}, "A=-1 B=x y"},
+		{"unexported only", struct{ a int }{1}, ""},
+		{"pointer", &structBase{ID: 7}, "ID=7"},
+		{"nil pointer", (*structBase)(nil), "<nil>"},
+		{"not a struct", 42, "42"},
+		{"mixed", structMixed{
+			structBase: structBase{ID: 1},
+			Name:       "gopher",
+			Age:        12,
+			Score:      9.5,
+			Active:     true,
+			Tags:       []string{"a", "b"},
+			Inner:      structInner{City: "Denver", Deep: structBase{ID: 2}},
+			Ptr:        &structInner{City: "Austin"},
+			private:    "hidden",
+		}, "structBase={ID=1} Name=gopher Age=12 Score=9.5 Active=true Tags=[a b] " +
+			"Inner={City=Denver Deep={ID:2}} Ptr={City=Austin Deep={ID:0}} NilPtr=<nil> Any=<nil>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			WriteStruct(sb, tt.v)
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteStruct() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// builderSize is the size of a strings.Builder, not
+// counting its backing array.
+const builderSize = int64(unsafe.Sizeof(strings.Builder{}))
+
+// retainedBytes is the estimated number of bytes held by
+// the builders parked in one pool. Its fields are accessed
+// atomically.
+type retainedBytes struct {
+	n      int64
+	listed int32 // 1 while in the registry
+}
+
+// registry tracks the retained byte counters of all pools
+// created with New. It holds the counters rather than the
+// pools themselves so that unreachable pools can still be
+// collected; a finalizer removes their counters. Drain also
+// removes a pool's counter, and the next builder parked in
+// the pool adds it back.
+var registry = struct {
+	sync.Mutex
+	m map[*retainedBytes]struct{}
+}{m: make(map[*retainedBytes]struct{})}
+
+// register adds bp to the registry.
+func register(bp *StringPool) {
+	bp.retained = &retainedBytes{}
+	list(bp.retained)
+
+	runtime.SetFinalizer(bp, func(bp *StringPool) {
+		unregister(bp.retained)
+	})
+}
+
+// list adds a retained byte counter to the registry.
+func list(r *retainedBytes) {
+	registry.Lock()
+	registry.m[r] = struct{}{}
+	atomic.StoreInt32(&r.listed, 1)
+	registry.Unlock()
+}
+
+// unregister removes a retained byte counter from the registry.
+func unregister(r *retainedBytes) {
+	registry.Lock()
+	delete(registry.m, r)
+	atomic.StoreInt32(&r.listed, 0)
+	registry.Unlock()
+}
+
+// TotalRetainedBytes returns a rough upper bound on the
+// number of bytes held by the builders parked in all pools
+// created with New, including the global pool.
+//
+// Each parked builder counts as the size of a
+// strings.Builder plus the capacity it keeps. Release resets
+// builders, which lets go of their backing arrays, so only
+// builders that are parked with capacity, such as those
+// warmed by Calibrate, count for more than the struct. A Get
+// subtracts the builder it takes out again, but the
+// sync.Pool underlying each StringPool drops parked builders
+// during garbage collection without notice, and those are
+// never subtracted: between Drains, the figure only grows.
+// It is not the memory currently retained and is not fit to
+// alert on; use it to spot pools that park many more
+// builders than expected. A drained pool does not count
+// toward the total until a builder is parked in it again.
+func TotalRetainedBytes() int64 {
+	registry.Lock()
+	defer registry.Unlock()
+
+	var total int64
+	for r := range registry.m {
+		total += atomic.LoadInt64(&r.n)
+	}
+	return total
+}
+
+// RetainedBytes returns a rough upper bound on the number of
+// bytes held by the builders parked in the pool. See
+// TotalRetainedBytes for how it is counted.
+func (bp *StringPool) RetainedBytes() int64 {
+	if bp.retained == nil {
+		return 0
+	}
+	return atomic.LoadInt64(&bp.retained.n)
+}
+
+// addRetained adjusts the pool's retained byte estimate by
+// the size of sb, which is being parked (sign 1) or taken
+// out of the pool (sign -1).
+func (bp *StringPool) addRetained(sb *strings.Builder, sign int64) {
+	if bp.retained == nil {
+		return
+	}
+	if sign > 0 && atomic.LoadInt32(&bp.retained.listed) == 0 {
+		list(bp.retained)
+	}
+	atomic.AddInt64(&bp.retained.n, sign*(builderSize+int64(sb.Cap())))
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTotalRetainedBytes(t *testing.T) {
+	// TotalRetainedBytes sums the counters of every live pool
+	// in the process, so check these pools and that they are
+	// registered rather than diffing the total.
+	p1, p2 := New(), New()
+	for i := 0; i < 3; i++ {
+		sb := &strings.Builder{}
+		sb.WriteString("retained")
+		p1.Release(sb)
+	}
+	for i := 0; i < 2; i++ {
+		p2.Release(&strings.Builder{})
+	}
+
+	if got, want := p1.RetainedBytes(), 3*builderSize; got != want {
+		t.Errorf("p1.RetainedBytes() = %v, want %v", got, want)
+	}
+	if got, want := p2.RetainedBytes(), 2*builderSize; got != want {
+		t.Errorf("p2.RetainedBytes() = %v, want %v", got, want)
+	}
+	if !listed(p1) || !listed(p2) {
+		t.Errorf("New() pools are not both counted by TotalRetainedBytes()")
+	}
+}
+
+func TestRetainedBytesCalibrate(t *testing.T) {
+	p := New()
+	p.Calibrate([]int{1024})
+	if got, want := p.RetainedBytes(), calibrateWarm*(builderSize+1024); got < want {
+		t.Errorf("RetainedBytes() after Calibrate = %v, want >= %v", got, want)
+	}
+
+	// taking a builder out of the pool is never counted as
+	// more than it was when parked
+	sb := p.Get()
+	if got := p.RetainedBytes(); got < 0 {
+		t.Errorf("RetainedBytes() after Get = %v, want >= 0", got)
+	}
+	p.Release(sb)
+}
+
+// listed reports whether p's counter is in the registry.
+func listed(p *StringPool) bool {
+	registry.Lock()
+	defer registry.Unlock()
+	_, ok := registry.m[p.retained]
+	return ok
+}
+
+func TestTotalRetainedBytesDrain(t *testing.T) {
+	p := New()
+	for i := 0; i < 4; i++ {
+		p.Release(&strings.Builder{})
+	}
+	if !listed(p) {
+		t.Fatal("New() pool is not registered")
+	}
+
+	if got, want := p.RetainedBytes(), 4*builderSize; got != want {
+		t.Fatalf("RetainedBytes() = %v, want %v", got, want)
+	}
+	p.Drain()
+	if listed(p) {
+		t.Error("drained pool is still registered")
+	}
+	if got := p.RetainedBytes(); got != 0 {
+		t.Errorf("RetainedBytes() after Drain() = %v, want 0", got)
+	}
+
+	// parking a builder in the drained pool registers it again
+	p.Release(&strings.Builder{})
+	if !listed(p) {
+		t.Error("pool is not registered again after Release()")
+	}
+	if got, want := p.RetainedBytes(), builderSize; got != want {
+		t.Errorf("RetainedBytes() after Release() = %v, want %v", got, want)
+	}
+}
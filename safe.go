@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+//go:build stringpool_safe
+// +build stringpool_safe
+
+package stringpool
+
+import (
+	"strings"
+	"sync"
+)
+
+// guardRing is the number of recently released builders
+// remembered by a releaseGuard.
+const guardRing = 64
+
+// releaseGuard detects a builder being released twice
+// without a Get in between, which would park it twice and
+// later hand the same builder to two callers at once. It is
+// compiled in with the stringpool_safe build tag:
+//
+//	go test -tags stringpool_safe ./...
+//
+// The guard remembers the last guardRing builders released
+// and not yet handed out again, and every Release and Get
+// scans that ring under a mutex. This serializes the pool and
+// is meant for tests and debugging, not production. A double
+// release is only caught while the first release is among
+// the last guardRing.
+//
+// Without the tag, releaseGuard is an empty struct and the
+// checks compile away.
+type releaseGuard struct {
+	mu   sync.Mutex
+	ring [guardRing]*strings.Builder
+	next int
+}
+
+// release records that sb is being released. It reports
+// false if sb was already released and not handed out since,
+// in which case the release must be ignored.
+func (g *releaseGuard) release(sb *strings.Builder) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, p := range g.ring {
+		if p == sb {
+			return false
+		}
+	}
+	g.ring[g.next] = sb
+	g.next = (g.next + 1) % guardRing
+	return true
+}
+
+// get records that sb has been handed out again.
+func (g *releaseGuard) get(sb *strings.Builder) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i, p := range g.ring {
+		if p == sb {
+			g.ring[i] = nil
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+//go:build stringpool_safe
+// +build stringpool_safe
+
+package stringpool
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDoubleRelease(t *testing.T) {
+	l := &testLogger{}
+	p := New(WithLogger(l))
+
+	sb := p.Get()
+	sb.WriteString("hello")
+	p.Release(sb)
+	p.Release(sb)
+
+	// only one copy of sb may be retrievable
+	a, b := p.Get(), p.Get()
+	if a == b {
+		t.Errorf("two Gets after a double Release returned the same builder")
+	}
+	if got := p.Stats().Releases; got != 1 {
+		t.Errorf("Stats().Releases = %d, want 1", got)
+	}
+	if msgs := l.messages(); len(msgs) != 1 || !strings.Contains(msgs[0], "released twice") {
+		t.Errorf("logged %q, want one double release warning", msgs)
+	}
+}
+
+func TestReleaseAfterGet(t *testing.T) {
+	p := New()
+
+	// a builder handed out again may be released again
+	for i := 0; i < 3; i++ {
+		sb := p.Get()
+		p.Release(sb)
+	}
+	if got := p.Stats().Releases; got != 3 {
+		t.Errorf("Stats().Releases = %d, want 3", got)
+	}
+}
+
+func TestDoubleReleaseStrict(t *testing.T) {
+	p := New()
+	sb := p.Get()
+	p.Release(sb)
+
+	withStrictMode(true, func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("double Release() did not panic in strict mode")
+			}
+		}()
+		p.Release(sb)
+	})
+}
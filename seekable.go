@@ -0,0 +1,179 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "errors"
+
+// MaxSeekableLen is the largest length a SeekableBuilder
+// grows to. It bounds the zero filled gap a WriteAt far past
+// the end would allocate.
+const MaxSeekableLen = 1 << 30
+
+var (
+	// ErrNegativeOffset is returned by SeekableBuilder.WriteAt
+	// for a negative offset.
+	ErrNegativeOffset = errors.New("stringpool: negative offset")
+
+	// ErrOffsetTooLarge is returned by the write methods of
+	// SeekableBuilder for a write that would end beyond
+	// MaxSeekableLen.
+	ErrOffsetTooLarge = errors.New("stringpool: offset too large")
+)
+
+// sliceClasses are the capacities of the pooled byte slices
+// that back SeekableBuilders. Larger slices are not pooled.
+var sliceClasses = [...]int{64, 256, 1 << 10, 4 << 10, 16 << 10, 64 << 10}
+
+// slicePools holds one BytesPool per size class. The
+// largest class is DefaultMaxRetainedCap, so the zero value
+// of each pool keeps every slice putSlice hands it.
+var slicePools [len(sliceClasses)]BytesPool
+
+// sliceClass returns the index of the smallest size class
+// that holds n bytes, or -1 if n exceeds the largest class.
+func sliceClass(n int) int {
+	for i, c := range sliceClasses {
+		if n <= c {
+			return i
+		}
+	}
+	return -1
+}
+
+// getSlice returns an empty byte slice with a capacity of
+// at least n, from the matching size class if there is one.
+func getSlice(n int) *[]byte {
+	i := sliceClass(n)
+	if i < 0 {
+		b := make([]byte, 0, n)
+		return &b
+	}
+	b := slicePools[i].Get()
+	if cap(*b) < sliceClasses[i] {
+		*b = make([]byte, 0, sliceClasses[i])
+	}
+	return b
+}
+
+// putSlice returns b to the size class matching its
+// capacity. Slices that do not exactly match a class are
+// dropped.
+func putSlice(b *[]byte) {
+	i := sliceClass(cap(*b))
+	if i < 0 || cap(*b) != sliceClasses[i] {
+		return
+	}
+	slicePools[i].Release(b)
+}
+
+// SeekableBuilder is a text buffer that, unlike
+// strings.Builder, allows earlier positions to be rewritten
+// with WriteAt, e.g. to fill in a length or checksum once
+// the content after it is known. It is backed by a byte
+// slice drawn from pools of size classes.
+//
+// The zero value is ready to use. Call Release when done to
+// return the backing slice to its pool; the SeekableBuilder
+// is empty and may be reused afterwards.
+type SeekableBuilder struct {
+	buf *[]byte
+}
+
+// NewSeekableBuilder returns a SeekableBuilder with room for
+// at least n bytes.
+func NewSeekableBuilder(n int) *SeekableBuilder {
+	return &SeekableBuilder{buf: getSlice(n)}
+}
+
+// Len returns the number of bytes written so far.
+func (b *SeekableBuilder) Len() int {
+	if b.buf == nil {
+		return 0
+	}
+	return len(*b.buf)
+}
+
+// String returns a copy of the accumulated content.
+func (b *SeekableBuilder) String() string {
+	if b.buf == nil {
+		return ""
+	}
+	return string(*b.buf)
+}
+
+// Write appends p to the buffer. It returns len(p) and a nil
+// error, or ErrOffsetTooLarge if the buffer would grow beyond
+// MaxSeekableLen.
+func (b *SeekableBuilder) Write(p []byte) (int, error) {
+	return b.WriteAt(p, int64(b.Len()))
+}
+
+// WriteString appends s to the buffer. It returns len(s) and
+// a nil error, or ErrOffsetTooLarge if the buffer would grow
+// beyond MaxSeekableLen.
+func (b *SeekableBuilder) WriteString(s string) (int, error) {
+	n := b.Len()
+	if len(s) > MaxSeekableLen-n {
+		return 0, ErrOffsetTooLarge
+	}
+	b.grow(n + len(s))
+	copy((*b.buf)[n:], s)
+	return len(s), nil
+}
+
+// WriteAt writes p at offset off, overwriting existing
+// content. Writing past the end grows the buffer; any gap
+// between the old end and off is filled with zero bytes.
+// A write that would end beyond MaxSeekableLen is rejected
+// with ErrOffsetTooLarge. WriteAt implements io.WriterAt.
+func (b *SeekableBuilder) WriteAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, ErrNegativeOffset
+	}
+	if off > MaxSeekableLen || int64(len(p)) > MaxSeekableLen-off {
+		return 0, ErrOffsetTooLarge
+	}
+	if end := int(off) + len(p); end > b.Len() {
+		b.grow(end)
+	}
+	copy((*b.buf)[off:], p)
+	return len(p), nil
+}
+
+// Release returns the backing slice to its pool and empties
+// the SeekableBuilder.
+func (b *SeekableBuilder) Release() {
+	if b.buf == nil {
+		return
+	}
+	putSlice(b.buf)
+	b.buf = nil
+}
+
+// grow extends the buffer to length n, zero filling any new
+// bytes and moving to a larger pooled slice if needed.
+func (b *SeekableBuilder) grow(n int) {
+	if b.buf == nil {
+		b.buf = getSlice(n)
+	}
+	old := len(*b.buf)
+	if n <= old {
+		return
+	}
+	if n > cap(*b.buf) {
+		size := 2 * cap(*b.buf)
+		if size < n {
+			size = n
+		}
+		nb := getSlice(size)
+		*nb = append(*nb, *b.buf...)
+		putSlice(b.buf)
+		b.buf = nb
+	}
+	*b.buf = (*b.buf)[:n]
+	for i := old; i < n; i++ {
+		(*b.buf)[i] = 0
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+var _ io.WriterAt = (*SeekableBuilder)(nil)
+
+func TestSeekableBuilder(t *testing.T) {
+	type write struct {
+		s   string
+		off int64 // -1 appends
+	}
+	tests := []struct {
+		name   string
+		writes []write
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"append", []write{{"hello", -1}, {", world", -1}}, "hello, world"},
+		{"overwrite start", []write{{"hello", -1}, {"J", 0}}, "Jello"},
+		{"overwrite middle", []write{{"0000000000", -1}, {"abc", 4}}, "0000abc000"},
+		{"overlap end", []write{{"abcd", -1}, {"XYZ", 2}}, "abXYZ"},
+		{"placeholder", []write{{"len=????;", -1}, {"body", -1}, {"0004", 4}}, "len=0004;body"},
+		{"gap", []write{{"ab", -1}, {"cd", 4}}, "ab\x00\x00cd"},
+		{"from zero value", []write{{"x", 3}}, "\x00\x00\x00x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b SeekableBuilder
+			defer b.Release()
+
+			for _, w := range tt.writes {
+				if w.off < 0 {
+					b.WriteString(w.s)
+					continue
+				}
+				if n, err := b.WriteAt([]byte(w.s), w.off); n != len(w.s) || err != nil {
+					t.Fatalf("WriteAt() = %v, %v, want %v, nil", n, err, len(w.s))
+				}
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("SeekableBuilder.String() = %q, want %q", got, tt.want)
+			}
+			if got := b.Len(); got != len(tt.want) {
+				t.Errorf("SeekableBuilder.Len() = %v, want %v", got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSeekableBuilderGrow(t *testing.T) {
+	b := NewSeekableBuilder(8)
+	defer b.Release()
+
+	b.WriteString("head")
+	// far beyond the first size class
+	big := strings.Repeat("x", 5000)
+	b.WriteAt([]byte(big), 10000)
+	b.WriteAt([]byte("HEAD"), 0)
+
+	if got := b.Len(); got != 15000 {
+		t.Fatalf("SeekableBuilder.Len() = %v, want %v", got, 15000)
+	}
+	s := b.String()
+	if !strings.HasPrefix(s, "HEAD\x00") || !strings.HasSuffix(s, big) {
+		t.Errorf("SeekableBuilder.String() = %.16q...%.16q, want HEAD...x", s, s[len(s)-16:])
+	}
+}
+
+func TestSeekableBuilderBadOffset(t *testing.T) {
+	tests := []struct {
+		name string
+		off  int64
+		want error
+	}{
+		{"negative", -1, ErrNegativeOffset},
+		{"past max", MaxSeekableLen + 1, ErrOffsetTooLarge},
+		{"end past max", MaxSeekableLen, ErrOffsetTooLarge},
+		{"end overflows", math.MaxInt64, ErrOffsetTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b SeekableBuilder
+			if n, err := b.WriteAt([]byte("x"), tt.off); n != 0 || !errors.Is(err, tt.want) {
+				t.Errorf("WriteAt(%d) = %v, %v, want 0, %v", tt.off, n, err, tt.want)
+			}
+			if got := b.Len(); got != 0 {
+				t.Errorf("Len() after a rejected WriteAt() = %v, want 0", got)
+			}
+		})
+	}
+}
+
+func TestSliceClasses(t *testing.T) {
+	tests := []struct {
+		n       int
+		wantCap int
+	}{
+		{0, 64},
+		{64, 64},
+		{65, 256},
+		{64 << 10, 64 << 10},
+		{64<<10 + 1, 64<<10 + 1},
+	}
+	for _, tt := range tests {
+		b := getSlice(tt.n)
+		if got := cap(*b); got != tt.wantCap {
+			t.Errorf("getSlice(%d) cap = %v, want %v", tt.n, got, tt.wantCap)
+		}
+		if len(*b) != 0 {
+			t.Errorf("getSlice(%d) len = %v, want 0", tt.n, len(*b))
+		}
+		putSlice(b)
+	}
+}
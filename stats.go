@@ -0,0 +1,254 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// warnMinGets is the number of Gets a pool must have
+	// served before its effectiveness is judged.
+	warnMinGets = 100
+
+	// warnMissRatio is the fraction of Gets that must
+	// allocate a new builder before the pool is considered
+	// ineffective.
+	warnMissRatio = 0.5
+)
+
+// warnInterval is the minimum time between effectiveness
+// warnings from a single pool.
+var warnInterval = time.Minute
+
+// Stats is a snapshot of the counters kept by a StringPool.
+type Stats struct {
+	// Gets is the number of builders handed out by Get.
+	Gets uint64 `json:"gets"`
+
+	// Releases is the number of builders returned by Release.
+	Releases uint64 `json:"releases"`
+
+	// News is the number of builders allocated, whether for a
+	// Get that missed or to warm the pool (see Calibrate).
+	News uint64 `json:"news"`
+
+	// Misses is the number of Gets that found no builder to
+	// reuse and allocated a new one.
+	Misses uint64 `json:"misses"`
+
+	// Discards is the number of released builders dropped
+	// rather than parked because their capacity exceeded the
+	// pool's maximum (see WithMaxRetainedCap), or because the
+	// free list of a TinyGo or stringpool_freelist build was
+	// full.
+	Discards uint64 `json:"discards"`
+
+	// OutOfOrderReleases is the number of builders released
+	// while a more recently obtained builder was still
+	// outstanding. It is only counted in debug mode; see
+	// WithDebug.
+	OutOfOrderReleases uint64 `json:"out_of_order_releases"`
+
+	// MaxBuildLen is the largest length of a builder at the
+	// time it was released: the longest string the pool has
+	// produced. It is useful for sizing capacity limits.
+	MaxBuildLen uint64 `json:"max_build_len"`
+
+	// Outstanding is the number of builders currently checked
+	// out: handed out by Get, or registered with Adopt, and
+	// not yet released. Unlike the counters it is a gauge; it
+	// does not go below zero, so releasing a builder the pool
+	// never handed out does not hide a later leak.
+	Outstanding int64 `json:"outstanding"`
+}
+
+// poolStats holds the live counters of a StringPool. All
+// fields are accessed atomically.
+type poolStats struct {
+	gets     uint64
+	releases uint64
+	news     uint64
+	misses   uint64
+	discards uint64
+
+	outOfOrder  uint64
+	maxBuildLen uint64
+	outstanding int64
+
+	// lastWarn is the time of the last effectiveness
+	// warning, in Unix nanoseconds.
+	lastWarn int64
+}
+
+// Stats returns a snapshot of the pool's counters. It is
+// safe to call while the pool is in use; the counters are
+// read individually and may not be mutually consistent.
+func (bp *StringPool) Stats() Stats {
+	return Stats{
+		Gets:     atomic.LoadUint64(&bp.stats.gets),
+		Releases: atomic.LoadUint64(&bp.stats.releases),
+		News:     atomic.LoadUint64(&bp.stats.news),
+		Misses:   atomic.LoadUint64(&bp.stats.misses),
+		Discards: atomic.LoadUint64(&bp.stats.discards),
+
+		OutOfOrderReleases: atomic.LoadUint64(&bp.stats.outOfOrder),
+		MaxBuildLen:        atomic.LoadUint64(&bp.stats.maxBuildLen),
+		Outstanding:        atomic.LoadInt64(&bp.stats.outstanding),
+	}
+}
+
+// Outstanding returns the number of builders currently
+// checked out of the pool. See Stats.Outstanding.
+func (bp *StringPool) Outstanding() int64 {
+	return atomic.LoadInt64(&bp.stats.outstanding)
+}
+
+// checkOut records that a builder was checked out.
+func (bp *StringPool) checkOut() {
+	atomic.AddInt64(&bp.stats.outstanding, 1)
+}
+
+// checkIn records that a builder was returned, unless none
+// is checked out.
+func (bp *StringPool) checkIn() {
+	for {
+		n := atomic.LoadInt64(&bp.stats.outstanding)
+		if n <= 0 || atomic.CompareAndSwapInt64(&bp.stats.outstanding, n, n-1) {
+			return
+		}
+	}
+}
+
+// Efficiency returns the fraction of Gets that reused a
+// parked builder rather than allocating one: 1 - Misses/Gets.
+// A value near 1 means the pool is reusing builders well; a
+// value near 0 means it is not helping, e.g. because
+// builders are not released. Builders allocated to warm the
+// pool (see Calibrate) are not misses and do not lower it.
+// With no Gets it returns 0.
+func (s Stats) Efficiency() float64 {
+	if s.Gets == 0 {
+		return 0
+	}
+	return 1 - float64(s.Misses)/float64(s.Gets)
+}
+
+// Efficiency returns the fraction of the pool's Gets that
+// reused a parked builder. See Stats.Efficiency.
+func (bp *StringPool) Efficiency() float64 {
+	return bp.Stats().Efficiency()
+}
+
+// ResetStats sets the pool's counters back to zero, e.g.
+// between the phases of a benchmark. Gets and Releases
+// still in flight may be counted on either side of the
+// reset. The Outstanding gauge is left alone, as the
+// builders it counts are still checked out.
+func (bp *StringPool) ResetStats() {
+	atomic.StoreUint64(&bp.stats.gets, 0)
+	atomic.StoreUint64(&bp.stats.releases, 0)
+	atomic.StoreUint64(&bp.stats.news, 0)
+	atomic.StoreUint64(&bp.stats.misses, 0)
+	atomic.StoreUint64(&bp.stats.discards, 0)
+	atomic.StoreUint64(&bp.stats.outOfOrder, 0)
+	atomic.StoreUint64(&bp.stats.maxBuildLen, 0)
+}
+
+// StatsJSON returns the pool's current stats as a JSON
+// object, e.g. for a /debug/pools endpoint. The keys are the
+// snake_case names of the Stats fields.
+//
+// The stats are captured before the JSON is encoded into a
+// builder from the global pool, so the encoding itself is
+// not reflected in them.
+func (bp *StringPool) StatsJSON() ([]byte, error) {
+	stats := bp.Stats()
+
+	sb := Get()
+	defer Release(sb)
+
+	if err := json.NewEncoder(sb).Encode(stats); err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimSuffix(sb.String(), "\n")), nil
+}
+
+// recordBuildLen raises MaxBuildLen to n if n is larger.
+func (bp *StringPool) recordBuildLen(n int) {
+	for {
+		max := atomic.LoadUint64(&bp.stats.maxBuildLen)
+		if uint64(n) <= max {
+			return
+		}
+		if atomic.CompareAndSwapUint64(&bp.stats.maxBuildLen, max, uint64(n)) {
+			return
+		}
+	}
+}
+
+// checkEffective logs a rate limited warning if most Gets
+// have needed a newly allocated builder, which means the
+// pool is not helping, e.g. because builders are never
+// released.
+func (bp *StringPool) checkEffective() {
+	if bp.logger == nil {
+		return
+	}
+
+	gets := atomic.LoadUint64(&bp.stats.gets)
+	misses := atomic.LoadUint64(&bp.stats.misses)
+	if gets < warnMinGets || float64(misses) < warnMissRatio*float64(gets) {
+		return
+	}
+
+	now := time.Now().UnixNano()
+	last := atomic.LoadInt64(&bp.stats.lastWarn)
+	if last != 0 && now-last < int64(warnInterval) {
+		return
+	}
+	if !atomic.CompareAndSwapInt64(&bp.stats.lastWarn, last, now) {
+		return
+	}
+
+	bp.logger.Printf("stringpool: pool is ineffective: %d of %d Gets allocated a new builder", misses, gets)
+}
+
+// StatsDiff holds the per-metric differences between the
+// stats of two pools. Each field is the value for the second
+// pool minus the value for the first, so positive values
+// mean the second pool counted more.
+type StatsDiff struct {
+	Gets               int64
+	Releases           int64
+	News               int64
+	Misses             int64
+	Discards           int64
+	OutOfOrderReleases int64
+	MaxBuildLen        int64
+	Outstanding        int64
+}
+
+// DiffStats compares the current stats of pools a and b and
+// returns b's counters minus a's. It is intended for A/B
+// comparisons of pool configurations, e.g. running two
+// configurations side by side and comparing how often each
+// had to allocate a new builder.
+func DiffStats(a, b *StringPool) StatsDiff {
+	sa, sb := a.Stats(), b.Stats()
+	return StatsDiff{
+		Gets:               int64(sb.Gets - sa.Gets),
+		Releases:           int64(sb.Releases - sa.Releases),
+		News:               int64(sb.News - sa.News),
+		Misses:             int64(sb.Misses - sa.Misses),
+		Discards:           int64(sb.Discards - sa.Discards),
+		OutOfOrderReleases: int64(sb.OutOfOrderReleases - sa.OutOfOrderReleases),
+		MaxBuildLen:        int64(sb.MaxBuildLen - sa.MaxBuildLen),
+		Outstanding:        sb.Outstanding - sa.Outstanding,
+	}
+}
@@ -0,0 +1,382 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testLogger records everything logged through it.
+type testLogger struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.msgs = append(l.msgs, fmt.Sprintf(format, v...))
+}
+
+func (l *testLogger) messages() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.msgs...)
+}
+
+func TestStats(t *testing.T) {
+	p := New()
+	sbs := make([]*strings.Builder, 10)
+	for i := range sbs {
+		sbs[i] = p.Get()
+	}
+	for _, sb := range sbs[:4] {
+		p.Release(sb)
+	}
+
+	got := p.Stats()
+	if got.Gets != 10 {
+		t.Errorf("Stats().Gets = %v, want %v", got.Gets, 10)
+	}
+	if got.Releases != 4 {
+		t.Errorf("Stats().Releases = %v, want %v", got.Releases, 4)
+	}
+	if got.News != 10 {
+		t.Errorf("Stats().News = %v, want %v", got.News, 10)
+	}
+}
+
+func TestStatsMisses(t *testing.T) {
+	const n = 8
+	p := New()
+
+	sbs := make([]*strings.Builder, n)
+	for i := range sbs {
+		sbs[i] = p.Get()
+	}
+	if got := p.Stats().Misses; got != n {
+		t.Errorf("Stats().Misses after %d Gets = %v, want %v", n, got, n)
+	}
+	for _, sb := range sbs {
+		p.Release(sb)
+	}
+
+	// with builders parked, Gets may reuse them; sync.Pool
+	// gives no guarantee, so only an upper bound holds
+	p.Release(p.Get())
+	if got := p.Stats().Misses; got > n+1 {
+		t.Errorf("Stats().Misses = %v, want <= %v", got, n+1)
+	}
+}
+
+func TestStatsDiscards(t *testing.T) {
+	p := New(WithMaxRetainedCap(64))
+
+	small := p.Get()
+	large := p.Get()
+	large.Grow(128)
+	p.Release(small)
+	p.Release(large)
+
+	got := p.Stats()
+	if got.Discards != 1 {
+		t.Errorf("Stats().Discards = %v, want %v", got.Discards, 1)
+	}
+	if got.Releases != 2 {
+		t.Errorf("Stats().Releases = %v, want %v", got.Releases, 2)
+	}
+}
+
+func TestEfficiency(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats Stats
+		want  float64
+	}{
+		{"no gets", Stats{}, 0},
+		{"all reused", Stats{Gets: 10}, 1},
+		{"none reused", Stats{Gets: 10, Misses: 10}, 0},
+		{"mostly reused", Stats{Gets: 100, Misses: 4}, 0.96},
+		{"warm-up news ignored", Stats{Gets: 4, News: 8, Misses: 1}, 0.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.Efficiency(); got != tt.want {
+				t.Errorf("Stats.Efficiency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoolEfficiency(t *testing.T) {
+	// four builders held at once, then released; a sync.Pool
+	// may drop parked builders, so the reuse rounds can only
+	// bound efficiency from above
+	p := New()
+	sbs := make([]*strings.Builder, 4)
+	for i := range sbs {
+		sbs[i] = p.Get()
+	}
+	if got := p.Efficiency(); got != 0 {
+		t.Errorf("Efficiency() with nothing released = %v, want 0", got)
+	}
+	for _, sb := range sbs {
+		p.Release(sb)
+	}
+	for i := 0; i < 4; i++ {
+		p.Release(p.Get())
+	}
+	if got := p.Efficiency(); got < 0 || got > 0.5 {
+		t.Errorf("Efficiency() = %v, want in [0, 0.5]", got)
+	}
+
+	// a pool that never releases never reuses
+	q := New()
+	for i := 0; i < 8; i++ {
+		q.Get()
+	}
+	if got := q.Efficiency(); got != 0 {
+		t.Errorf("Efficiency() without releases = %v, want 0", got)
+	}
+}
+
+func TestResetStats(t *testing.T) {
+	p := New(WithMaxRetainedCap(1))
+	sb := p.Get()
+	sb.WriteString("hello")
+	p.Release(sb)
+
+	p.ResetStats()
+	if got := p.Stats(); got != (Stats{}) {
+		t.Errorf("Stats() after ResetStats() = %+v, want zero", got)
+	}
+
+	p.Get()
+	if got := p.Stats(); got.Gets != 1 || got.Misses != 1 {
+		t.Errorf("Stats() after ResetStats() and Get() = %+v, want 1 Get and 1 Miss", got)
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	tests := []struct {
+		name     string
+		release  bool
+		wantWarn int
+	}{
+		{"high miss", false, 1},
+		{"reused", true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &testLogger{}
+			p := New(WithLogger(l))
+
+			// builders that are never released force a new
+			// allocation on every Get
+			for i := 0; i < 10*warnMinGets; i++ {
+				sb := p.Get()
+				if tt.release {
+					p.Release(sb)
+				}
+			}
+
+			msgs := l.messages()
+			if len(msgs) != tt.wantWarn {
+				t.Fatalf("logged %d warnings, want %d: %q", len(msgs), tt.wantWarn, msgs)
+			}
+			if tt.wantWarn > 0 && !strings.Contains(msgs[0], "ineffective") {
+				t.Errorf("warning = %q, want it to mention %q", msgs[0], "ineffective")
+			}
+		})
+	}
+}
+
+func TestOutstanding(t *testing.T) {
+	p := New()
+	sbs := []*strings.Builder{p.Get(), p.Get(), p.Get()}
+	if got := p.Outstanding(); got != 3 {
+		t.Errorf("Outstanding() after 3 Gets = %d, want 3", got)
+	}
+
+	p.Release(sbs[0])
+	if got := p.Stats().Outstanding; got != 2 {
+		t.Errorf("Stats().Outstanding after a Release = %d, want 2", got)
+	}
+
+	// the gauge is not a counter and survives ResetStats
+	p.ResetStats()
+	if got := p.Outstanding(); got != 2 {
+		t.Errorf("Outstanding() after ResetStats() = %d, want 2", got)
+	}
+
+	adopted := &strings.Builder{}
+	p.Adopt(adopted)
+	p.Adopt(nil)
+	if got := p.Outstanding(); got != 3 {
+		t.Errorf("Outstanding() after Adopt() = %d, want 3", got)
+	}
+	p.Release(adopted)
+	p.Release(sbs[1])
+	p.Release(sbs[2])
+	if got := p.Outstanding(); got != 0 {
+		t.Errorf("Outstanding() after releasing all = %d, want 0", got)
+	}
+
+	// a foreign builder does not drive the gauge negative
+	p.Release(&strings.Builder{})
+	if got := p.Outstanding(); got != 0 {
+		t.Errorf("Outstanding() after foreign Release() = %d, want 0", got)
+	}
+	sb := p.Get()
+	if got := p.Outstanding(); got != 1 {
+		t.Errorf("Outstanding() after Get() = %d, want 1", got)
+	}
+	p.Release(sb)
+}
+
+func TestOutstandingConcurrent(t *testing.T) {
+	p := New()
+	h := p.GetHandle()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				sb := p.Get()
+				sb.WriteString("x")
+				p.Release(sb)
+				p.Build(func(sb *strings.Builder) { sb.WriteString("y") })
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := p.Outstanding(); got != 1 {
+		t.Errorf("Outstanding() with a Handle open = %d, want 1", got)
+	}
+	h.Close()
+	if got := p.Outstanding(); got != 0 {
+		t.Errorf("Outstanding() after all released = %d, want 0", got)
+	}
+}
+
+func TestStatsMaxBuildLen(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []int
+		want  uint64
+	}{
+		{"none", nil, 0},
+		{"empty builds", []int{0, 0}, 0},
+		{"increasing", []int{1, 10, 100}, 100},
+		{"decreasing", []int{100, 10, 1}, 100},
+		{"peak in middle", []int{5, 500, 50}, 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			for _, n := range tt.sizes {
+				sb := p.Get()
+				sb.WriteString(strings.Repeat("x", n))
+				p.Release(sb)
+			}
+			if got := p.Stats().MaxBuildLen; got != tt.want {
+				t.Errorf("Stats().MaxBuildLen = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsMaxBuildLenConcurrent(t *testing.T) {
+	p := New()
+	var wg sync.WaitGroup
+	for i := 1; i <= 64; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			sb := p.Get()
+			sb.WriteString(strings.Repeat("x", n))
+			p.Release(sb)
+		}(i)
+	}
+	wg.Wait()
+
+	if got := p.Stats().MaxBuildLen; got != 64 {
+		t.Errorf("Stats().MaxBuildLen = %v, want %v", got, 64)
+	}
+}
+
+func TestDiffStats(t *testing.T) {
+	// a reuses one builder; b never releases and so
+	// allocates a new builder for every Get
+	a, b := New(), New()
+	for i := 0; i < 10; i++ {
+		a.Release(a.Get())
+		b.Get()
+	}
+	sb := b.Get()
+	sb.WriteString("longer")
+	b.Release(sb)
+
+	want := StatsDiff{
+		Gets:        1,
+		Releases:    -9,
+		News:        int64(b.Stats().News) - int64(a.Stats().News),
+		Misses:      int64(b.Stats().Misses) - int64(a.Stats().Misses),
+		MaxBuildLen: 6,
+		Outstanding: 10,
+	}
+	got := DiffStats(a, b)
+	if got != want {
+		t.Errorf("DiffStats() = %+v, want %+v", got, want)
+	}
+	if got.News <= 0 {
+		t.Errorf("DiffStats().News = %v, want > 0", got.News)
+	}
+
+	if rev := DiffStats(b, a); rev.News != -got.News {
+		t.Errorf("DiffStats(b, a).News = %v, want %v", rev.News, -got.News)
+	}
+}
+
+func TestStatsJSON(t *testing.T) {
+	p := New()
+	for i := 0; i < 3; i++ {
+		sb := p.Get()
+		sb.WriteString(strings.Repeat("x", i+1))
+		p.Release(sb)
+	}
+	want := p.Stats()
+
+	b, err := p.StatsJSON()
+	if err != nil {
+		t.Fatalf("StatsJSON() error = %v", err)
+	}
+
+	var got Stats
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error = %v", b, err)
+	}
+	if got != want {
+		t.Errorf("StatsJSON() = %+v, want %+v", got, want)
+	}
+
+	var fields map[string]uint64
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error = %v", b, err)
+	}
+	if got := fields["max_build_len"]; got != 3 {
+		t.Errorf(`StatsJSON()["max_build_len"] = %v, want %v`, got, 3)
+	}
+	if got := fields["releases"]; got != 3 {
+		t.Errorf(`StatsJSON()["releases"] = %v, want %v`, got, 3)
+	}
+}
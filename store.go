@@ -0,0 +1,42 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+//go:build !tinygo && !stringpool_freelist
+// +build !tinygo,!stringpool_freelist
+
+package stringpool
+
+import (
+	"strings"
+	"sync"
+)
+
+// builderStore holds the idle builders of a StringPool. This
+// implementation is backed by a sync.Pool; see freelist.go
+// for the fallback used where sync.Pool is unsuitable.
+type builderStore struct {
+	p sync.Pool
+}
+
+// get returns an idle builder, or nil if there is none.
+func (s *builderStore) get() *strings.Builder {
+	if v := s.p.Get(); v != nil {
+		return v.(*strings.Builder)
+	}
+	return nil
+}
+
+// put parks sb for reuse. It always reports true; a
+// sync.Pool accepts every builder, though it may drop it
+// later.
+func (s *builderStore) put(sb *strings.Builder) bool {
+	s.p.Put(sb)
+	return true
+}
+
+// maxParked returns the most idle builders the store keeps;
+// a sync.Pool is not bounded.
+func (s *builderStore) maxParked() int {
+	return 0
+}
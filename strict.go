@@ -0,0 +1,46 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// strict is non-zero when strict mode is enabled.
+var strict int32
+
+// SetStrictMode sets the policy used by the helpers in this
+// package when they are misused, e.g. an odd number of
+// arguments to WriteObject or a non-string object key.
+//
+// In strict mode misuse panics, failing fast during
+// development. Otherwise (the default) the helpers skip the
+// bad input and return a best-effort result, which is more
+// robust in production.
+//
+// SetStrictMode is safe for use by multiple goroutines
+// simultaneously.
+func SetStrictMode(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&strict, v)
+}
+
+// StrictMode reports whether strict mode is enabled.
+func StrictMode() bool {
+	return atomic.LoadInt32(&strict) != 0
+}
+
+// misuse reports helper misuse. It panics in strict mode
+// and is a no-op otherwise; callers skip the bad input
+// after it returns.
+func misuse(format string, args ...interface{}) {
+	if StrictMode() {
+		panic("stringpool: " + fmt.Sprintf(format, args...))
+	}
+}
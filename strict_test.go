@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"testing"
+)
+
+// withStrictMode runs fn with strict mode set to on and
+// restores the previous setting afterwards.
+func withStrictMode(on bool, fn func()) {
+	defer SetStrictMode(StrictMode())
+	SetStrictMode(on)
+	fn()
+}
+
+func TestSetStrictMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		strict    bool
+		kv        []interface{}
+		want      string
+		wantPanic bool
+	}{
+		{"lenient odd", false, []interface{}{"a", 1, "b"}, `{"a":1}`, false},
+		{"lenient single", false, []interface{}{"a"}, `{}`, false},
+		{"lenient even", false, []interface{}{"a", 1}, `{"a":1}`, false},
+		{"strict odd", true, []interface{}{"a", 1, "b"}, "", true},
+		{"strict single", true, []interface{}{"a"}, "", true},
+		{"strict even", true, []interface{}{"a", 1}, `{"a":1}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStrictMode(tt.strict, func() {
+				defer func() {
+					r := recover()
+					if (r != nil) != tt.wantPanic {
+						t.Errorf("WriteObject() panic = %v, wantPanic %v", r, tt.wantPanic)
+					}
+				}()
+
+				sb := &strings.Builder{}
+				WriteObject(sb, tt.kv...)
+				if got := sb.String(); got != tt.want {
+					t.Errorf("WriteObject() = %q, want %q", got, tt.want)
+				}
+			})
+		})
+	}
+}
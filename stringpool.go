@@ -13,12 +13,13 @@
 // That is, it makes it easy to build efficient, thread-safe
 // free lists.
 //
-// Go 1.10 or later is required.
+// Go 1.23 or later is required; BuildSeq ranges over an
+// iter.Seq, which needs range-over-func support.
 package stringpool
 
 import (
 	"strings"
-	"sync"
+	"sync/atomic"
 )
 
 // StringPool is a sync.Pool for strings.Builder objects.
@@ -53,8 +54,39 @@ import (
 // free list.
 //
 // A Pool must not be copied after first use.
+//
+// The zero value is ready to use and behaves as a pool made
+// by New with no options, except that its parked builders
+// are not counted by RetainedBytes and TotalRetainedBytes:
+//
+//	var p stringpool.StringPool
+//	sb := p.Get()
+//	defer p.Release(sb)
+//
+// On TinyGo, and in builds with the stringpool_freelist tag,
+// a StringPool keeps its idle builders in a bounded,
+// mutex-guarded free list instead of a sync.Pool. The API is
+// the same.
 type StringPool struct {
-	pool sync.Pool
+	// counters are accessed atomically and are kept first
+	// in the struct for 64-bit alignment.
+	stats poolStats
+
+	// initialCap is the capacity new builders are grown to.
+	// It is accessed atomically.
+	initialCap int64
+
+	pool      atomic.Pointer[builderStore] // see store
+	logger    Logger
+	retained  *retainedBytes
+	debug     *debugState
+	maxCap    int // 0 for DefaultMaxRetainedCap, < 0 for no limit
+	allocator func(capHint int) *strings.Builder
+	poison    bool
+	limiter   *rateLimiter
+	adaptive  *adaptiveSize
+	leaks     *leakDetector
+	guard     releaseGuard
 }
 
 // global is the global StringPool used to allocate and
@@ -62,14 +94,44 @@ type StringPool struct {
 // not be copied after first use.
 var global *StringPool
 
+// current is the pool the package-level funcs delegate to;
+// it is global unless replaced by SetDefault.
+var current atomic.Pointer[StringPool]
+
 func init() {
 	global = New()
+	current.Store(global)
+}
+
+// Default returns the pool used by the package-level funcs
+// such as Get, Release and Build.
+func Default() *StringPool {
+	return current.Load()
+}
+
+// SetDefault makes p the pool used by the package-level
+// funcs, e.g. to give them a capacity cap or adaptive sizing
+// in a test without rewriting call sites. A nil p restores
+// the pool created at init. It is safe to call concurrently
+// with the package-level funcs, though a builder should be
+// released to the pool it was taken from.
+//
+// Deferring with the current pool as the argument restores
+// it afterwards:
+//
+//	defer stringpool.SetDefault(stringpool.Default())
+//	stringpool.SetDefault(stringpool.New(stringpool.WithMaxRetainedCap(4096)))
+func SetDefault(p *StringPool) {
+	if p == nil {
+		p = global
+	}
+	current.Store(p)
 }
 
 // newBuilder implements the sync.Pool interface
 // by providing the New method:
 //
-//  New Func() interface{}
+//	New Func() interface{}
 //
 // that specifically returns a strings.Builder
 //
@@ -80,16 +142,118 @@ func newBuilder() interface{} {
 
 // New returns a new StringPool instance. A StringPool is
 // used to allocate and release strings.Builder objects
-// as needed.
+// as needed. Options may be given to configure the pool.
 //
 // A Pool must not be copied after first use. A Pool
 // is safe for use by multiple goroutines simultaneously.
-func New() *StringPool {
+func New(opts ...Option) *StringPool {
 	bp := StringPool{}
-	bp.pool.New = newBuilder
+	for _, opt := range opts {
+		opt(&bp)
+	}
+	register(&bp)
 	return &bp
 }
 
+// Clone returns a new pool with the same configuration as
+// bp: initial capacity, max retained capacity, logger,
+// allocator, rate limit and the poison, debug, leak
+// detection and adaptive sizing modes. It is meant for
+// request-scoped work, so that a burst in one request does
+// not fill a long-lived pool with builders.
+//
+// Builders are NOT shared between bp and the clone: the
+// clone starts with no idle builders and zeroed stats, and
+// each pool only reuses builders released to it. A rate
+// limit is applied to each pool separately, and an adaptive
+// clone starts from the size bp has learned so far.
+func (bp *StringPool) Clone() *StringPool {
+	c := StringPool{
+		initialCap: int64(bp.initialCapacity()),
+		logger:     bp.logger,
+		maxCap:     bp.maxCap,
+		allocator:  bp.allocator,
+		poison:     bp.poison,
+	}
+	if bp.limiter != nil {
+		WithRateLimit(bp.limiter.perSecond)(&c)
+	}
+	if bp.debug != nil {
+		WithDebug()(&c)
+	}
+	if bp.leaks != nil {
+		WithLeakDetection()(&c)
+	}
+	if bp.adaptive != nil {
+		c.adaptive = &adaptiveSize{size: int64(bp.adaptive.learned())}
+	}
+	register(&c)
+	return &c
+}
+
+// store returns the store of idle builders, creating it on
+// first use by a zero-value pool. It is replaced by Drain.
+func (bp *StringPool) store() *builderStore {
+	if s := bp.pool.Load(); s != nil {
+		return s
+	}
+	bp.pool.CompareAndSwap(nil, &builderStore{})
+	return bp.pool.Load()
+}
+
+// Drain discards every builder parked in the global pool.
+// See StringPool.Drain.
+func Drain() {
+	Default().Drain()
+}
+
+// Drain discards every builder parked in the pool, so that
+// their memory can be collected promptly instead of after
+// the garbage collector empties the pool, e.g. during a
+// graceful shutdown or between the phases of a test. The
+// pool stays usable; the next Gets allocate new builders.
+//
+// Builders already handed out by Get are unaffected and may
+// be released as usual. The pool's counters are kept; see
+// ResetStats.
+//
+// A drained pool is removed from TotalRetainedBytes until a
+// builder is parked in it again.
+func (bp *StringPool) Drain() {
+	bp.pool.Store(&builderStore{})
+	if bp.retained != nil {
+		unregister(bp.retained)
+		atomic.StoreInt64(&bp.retained.n, 0)
+	}
+}
+
+// initialCapacity returns the capacity new builders are
+// grown to, as set by WithInitialCap or Calibrate.
+func (bp *StringPool) initialCapacity() int {
+	return int(atomic.LoadInt64(&bp.initialCap))
+}
+
+// alloc allocates a new builder and records the allocation.
+func (bp *StringPool) alloc() *strings.Builder {
+	atomic.AddUint64(&bp.stats.news, 1)
+
+	n := bp.initialCapacity()
+	if bp.adaptive != nil {
+		n = max(n, bp.adaptive.learned())
+	}
+
+	var sb *strings.Builder
+	if bp.allocator != nil {
+		sb = bp.allocator(n)
+	} else {
+		sb = newBuilder().(*strings.Builder)
+		if n > 0 {
+			sb.Grow(n)
+		}
+	}
+	return sb
+}
+
 // Get returns an empty strings.Builder from
 // the global pool.
 //
@@ -98,7 +262,22 @@ func New() *StringPool {
 // copying. The zero value is ready to use. Do
 // not copy a non-zero Builder.
 func Get() *strings.Builder {
-	return global.Get()
+	return Default().Get()
+}
+
+// GetWithCapacity returns an empty strings.Builder from the
+// global pool that has been grown to hold at least n bytes
+// without reallocating. If n <= 0 it is the same as Get.
+//
+// The grown capacity does not survive Release: resetting a
+// builder drops its buffer, so a builder obtained by a later
+// Get starts empty again (or at the pool's initial capacity,
+// see WithInitialCap). In steady state every
+// GetWithCapacity(n) therefore allocates n bytes, which
+// the caller would otherwise allocate in several smaller
+// steps while writing.
+func GetWithCapacity(n int) *strings.Builder {
+	return Default().GetWithCapacity(n)
 }
 
 // Release puts the given strings.Builder back into
@@ -111,8 +290,15 @@ func Get() *strings.Builder {
 // automatically at any time without notification.
 // If the Pool holds the only reference when this
 // happens, the item might be deallocated.
+//
+// Any builder may be released, not only one obtained from
+// Get: a builder created with &strings.Builder{} is reset
+// and donated to the pool like any other. See
+// StringPool.Release.
+//
+// Releasing a nil builder is a no-op.
 func Release(b *strings.Builder) {
-	global.Release(b)
+	Default().Release(b)
 }
 
 // Get returns an empty strings.Builder from
@@ -122,8 +308,52 @@ func Release(b *strings.Builder) {
 // string using Write methods. It minimizes memory
 // copying. The zero value is ready to use. Do
 // not copy a non-zero Builder.
-func (bp StringPool) Get() *strings.Builder {
-	return bp.pool.Get().(*strings.Builder)
+//
+// If the pool has a rate limit (see WithRateLimit), Get
+// blocks until the rate allows another Get.
+func (bp *StringPool) Get() *strings.Builder {
+	if bp.limiter != nil {
+		bp.limiter.wait()
+	}
+	return bp.get()
+}
+
+// get returns an empty builder from the pool, bypassing any
+// rate limit.
+func (bp *StringPool) get() *strings.Builder {
+	atomic.AddUint64(&bp.stats.gets, 1)
+	bp.checkOut()
+
+	sb := bp.store().get()
+	if sb != nil {
+		bp.addRetained(sb, -1)
+		bp.guard.get(sb)
+	} else {
+		atomic.AddUint64(&bp.stats.misses, 1)
+		sb = bp.alloc()
+		bp.checkEffective()
+	}
+	if bp.poison {
+		sb.Reset()
+	}
+	if bp.debug != nil {
+		bp.debugGet(sb)
+	}
+	if bp.leaks != nil {
+		bp.leakGet(sb)
+	}
+	return sb
+}
+
+// GetWithCapacity returns an empty strings.Builder from the
+// pool that has been grown to hold at least n bytes. See the
+// package level GetWithCapacity for details.
+func (bp *StringPool) GetWithCapacity(n int) *strings.Builder {
+	sb := bp.Get()
+	if n > 0 {
+		sb.Grow(n)
+	}
+	return sb
 }
 
 // Release puts the given strings.Builder back into
@@ -136,7 +366,167 @@ func (bp StringPool) Get() *strings.Builder {
 // automatically at any time without notification.
 // If the Pool holds the only reference when this
 // happens, the item might be deallocated.
-func (bp StringPool) Release(b *strings.Builder) {
-	b.Reset()
-	bp.pool.Put(b)
+//
+// The builder is always reset, so every Get returns an empty
+// builder. If the pool has a maximum retained capacity (see
+// WithMaxRetainedCap) and the builder's capacity exceeds
+// it, the builder is dropped rather than parked. Releasing
+// a nil builder is a no-op.
+//
+// b need not come from the pool. A builder created
+// elsewhere, e.g. before the code switched to the pool, is
+// handled the same way: it is reset, subject to the capacity
+// cap, and parked for a later Get to reuse; it is counted in
+// Stats().Releases but never was in Gets. A builder that is
+// a field of a larger struct keeps that struct alive while
+// parked, so only donate builders allocated on their own.
+// Pools created WithLeakDetection drop foreign builders
+// instead, as they cannot track them.
+//
+// Releasing a builder twice parks it twice, so that two
+// later Gets may share it. Builds with the stringpool_safe
+// tag detect this: the second release is ignored, logged to
+// the pool's logger (see WithLogger) and treated as misuse
+// according to SetStrictMode. The check serializes Release
+// and Get, so the tag is meant for tests; without it Release
+// is unchanged.
+//
+// See WithPoisonOnRelease for a test mode that exposes use of
+// a builder after it has been released.
+func (bp *StringPool) Release(b *strings.Builder) {
+	if b == nil {
+		return
+	}
+	if !bp.guard.release(b) {
+		bp.doubleRelease(b)
+		return
+	}
+	atomic.AddUint64(&bp.stats.releases, 1)
+	bp.checkIn()
+	bp.recordBuildLen(b.Len())
+	if bp.debug != nil {
+		bp.debugRelease(b)
+	}
+	foreign := bp.leaks != nil && !bp.leakRelease(b)
+	max := bp.maxRetainedCap()
+	if bp.adaptive != nil {
+		bp.adaptive.observe(b.Len(), max)
+	}
+	drop := foreign || max > 0 && b.Cap() > max
+	if bp.poison {
+		poisonBuilder(b)
+	} else {
+		b.Reset()
+	}
+	if drop {
+		atomic.AddUint64(&bp.stats.discards, 1)
+		return
+	}
+	bp.park(b)
+}
+
+// park puts sb in the store, counting it as retained, or as
+// discarded if the store is full.
+func (bp *StringPool) park(sb *strings.Builder) {
+	bp.addRetained(sb, 1)
+	if !bp.store().put(sb) {
+		bp.addRetained(sb, -1)
+		atomic.AddUint64(&bp.stats.discards, 1)
+	}
+}
+
+// ReleaseString returns the string built in b and releases
+// b to the global pool, collapsing the common
+//
+//	s := sb.String()
+//	stringpool.Release(sb)
+//
+// into a single call. The string remains valid after the
+// builder is reused; b must not be used again.
+func ReleaseString(b *strings.Builder) string {
+	return Default().ReleaseString(b)
+}
+
+// ReleaseString returns the string built in b and releases
+// b to the pool. See the package level ReleaseString for
+// details.
+func (bp *StringPool) ReleaseString(b *strings.Builder) string {
+	s := b.String()
+	bp.Release(b)
+	return s
+}
+
+// doubleRelease reports a second release of b, which has been
+// ignored.
+func (bp *StringPool) doubleRelease(b *strings.Builder) {
+	if bp.logger != nil {
+		bp.logger.Printf("stringpool: builder %p released twice; second release ignored", b)
+	}
+	misuse("Release: builder %p released twice", b)
+}
+
+// Adopt counts a strings.Builder that was created outside of
+// the global pool as checked out of it (see
+// Stats.Outstanding), so that its later Release balances the
+// gauge. Adopt does not change the builder or how Release
+// handles it: Release parks any builder for reuse, adopted
+// or not.
+//
+// Adopt is a migration shim. Code that currently declares
+// its own builder:
+//
+//	var sb strings.Builder
+//
+// may adopt it and release it when finished instead of
+// letting it become garbage:
+//
+//	sb := &strings.Builder{}
+//	stringpool.Adopt(sb)
+//	defer stringpool.Release(sb)
+//
+// The builder's contents are left untouched by Adopt; it
+// is reset when it is released. After Release, the builder
+// belongs to the pool and must not be used again by the
+// caller. Adopting a nil builder is a no-op.
+func Adopt(sb *strings.Builder) {
+	Default().Adopt(sb)
+}
+
+// Adopt counts a strings.Builder that was created outside of
+// the pool as checked out of it. See the package level Adopt
+// for details.
+func (bp *StringPool) Adopt(sb *strings.Builder) {
+	// Release parks any non-nil builder, regardless of where
+	// it was allocated; only the gauge needs to know.
+	if sb != nil {
+		bp.checkOut()
+	}
+}
+
+// GetReusable returns an empty strings.Builder from the
+// global pool together with a func that resets it. It is
+// intended for loops that build one string per iteration:
+// rather than a Get/Release pair per iteration, the loop
+// calls reset between iterations and releases the builder
+// once at the end:
+//
+//	sb, reset := stringpool.GetReusable()
+//	defer stringpool.Release(sb)
+//	for _, v := range values {
+//		reset()
+//		sb.WriteString(v)
+//		use(sb.String())
+//	}
+//
+// Strings returned by sb.String() remain valid after reset.
+func GetReusable() (*strings.Builder, func()) {
+	return Default().GetReusable()
+}
+
+// GetReusable returns an empty strings.Builder from the
+// pool together with a func that resets it. See the package
+// level GetReusable for details.
+func (bp *StringPool) GetReusable() (*strings.Builder, func()) {
+	sb := bp.Get()
+	return sb, sb.Reset
 }
@@ -13,30 +13,18 @@
 // That is, it makes it easy to build efficient, thread-safe
 // free lists.
 //
-// Go 1.10 or later is required.
+// Go 1.23 or later is required; BuildSeq ranges over an
+// iter.Seq, which needs range-over-func support.
 package stringpool
 
 import (
 	"reflect"
-	"strconv"
 	"strings"
 	"testing"
 )
 
-const (
-	// scaling factor (in powers of 2)
-	defaultMaxScalingFactor = 6
-	maxScalingFactor        = 10
-)
-
-type pooler interface {
-	Get() *strings.Builder
-	Release(sb *strings.Builder)
-}
-
 var (
 	sb         *strings.Builder = &strings.Builder{}
-	NewPool                     = New()
 	out        string           = "" // global string return value
 	global_n   int              = 0
 	global_err error            = nil
@@ -49,68 +37,16 @@ func (t swimmer) Get() *strings.Builder {
 	return new(strings.Builder)
 }
 
+// Release resets sb itself; resetting t would reset a copy
+// of the embedded builder and leave sb untouched.
 func (t swimmer) Release(sb *strings.Builder) {
-	t.Reset()
+	sb.Reset()
 }
 
-func sbNonPool() pooler {
+func sbNonPool() Pooler {
 	return &swimmer{}
 }
 
-func BenchmarkStringPool(b *testing.B) {
-	benchmarks := []struct {
-		name string
-		pool pooler
-		want string
-	}{
-		{"global", global, "global"},
-		{"newPool", New(), "newPool"},
-		{"non-pool", sbNonPool(), "non-pool"},
-	}
-
-	// set number of scaling factors and loop over them
-	maxScalingFactor := defaultMaxScalingFactor
-	for j := 0; j < maxScalingFactor; j++ {
-
-		// scaling by powers of 2
-		var scalingFactor = 1 << j
-
-		// cycle through benchmark list
-		for _, bb := range benchmarks {
-
-			b.Run(bb.name+"("+strconv.Itoa(scalingFactor)+")", func(b *testing.B) {
-
-				// setup and config
-				sb = bb.pool.Get()
-
-				// repeat benchmark b.N iterations
-				for i := 0; i < b.N; i++ {
-
-					// repeat various benchmark options
-					for k = 0; k < 255; k++ {
-
-						// scale internal repeats
-						for l := 0; l < scalingFactor; l++ {
-
-							// call to main benchmark function being tested
-							_ = sb.WriteByte(k)
-
-						}
-
-					}
-
-					// save to global variable to avoid compiler optimizations
-					out = sb.String()
-
-				}
-
-				// cleanups and resets
-				bb.pool.Release(sb)
-			})
-		}
-	}
-}
-
 func TestNew(t *testing.T) {
 	tests := []struct {
 		name string
@@ -129,6 +65,39 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestSetDefault(t *testing.T) {
+	defer SetDefault(Default())
+
+	p := New(WithMaxRetainedCap(64))
+	SetDefault(p)
+	if got := Default(); got != p {
+		t.Fatalf("Default() = %p, want %p", got, p)
+	}
+
+	before := global.Stats()
+	small := Get()
+	large := Get()
+	large.Grow(128)
+	Release(small)
+	Release(large)
+	if got := Build(func(sb *strings.Builder) { sb.WriteString("hello") }); got != "hello" {
+		t.Errorf("Build() = %q, want %q", got, "hello")
+	}
+
+	got := p.Stats()
+	if got.Gets != 3 || got.Releases != 3 || got.Discards != 1 {
+		t.Errorf("Stats() = %+v, want 3 Gets, 3 Releases and 1 Discard", got)
+	}
+	if after := global.Stats(); after != before {
+		t.Errorf("global Stats() changed from %+v to %+v", before, after)
+	}
+
+	SetDefault(nil)
+	if got := Default(); got != global {
+		t.Errorf("Default() after SetDefault(nil) = %p, want %p", got, global)
+	}
+}
+
 func TestGet(t *testing.T) {
 	fake := New()
 	fakeGet := fake.Get()
@@ -168,3 +137,377 @@ func TestRelease(t *testing.T) {
 		})
 	}
 }
+
+func TestReleaseResets(t *testing.T) {
+	tests := []struct {
+		name   string
+		pool   Pooler
+		resets bool // Release resets the builder it is given
+	}{
+		{"global", global, true},
+		{"newPool", New(), true},
+		{"max retained cap", New(WithMaxRetainedCap(1)), true},
+		{"non-pool", sbNonPool(), true},
+		{"NoPool", NoPool{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := tt.pool.Get()
+			sb.WriteString("hello")
+			tt.pool.Release(sb)
+
+			// the released builder itself is reset, not a copy
+			if tt.resets && sb.Len() != 0 {
+				t.Errorf("Release() left Len() = %d, want 0", sb.Len())
+			}
+			if got := tt.pool.Get().Len(); got != 0 {
+				t.Errorf("Get().Len() after Release = %d, want 0", got)
+			}
+		})
+	}
+}
+
+func TestReleaseNil(t *testing.T) {
+	p := New()
+	Release(nil)
+	p.Release(nil)
+
+	if got := p.Stats().Releases; got != 0 {
+		t.Errorf("Stats().Releases after Release(nil) = %d, want 0", got)
+	}
+}
+
+func TestClone(t *testing.T) {
+	parent := New(WithMaxRetainedCap(64), WithInitialCap(16), WithRateLimit(1000), WithAdaptiveSizing())
+	sb := parent.Get()
+	sb.WriteString("parent")
+	parent.Release(sb)
+
+	clone := parent.Clone()
+	if clone == parent {
+		t.Fatal("Clone() returned the parent")
+	}
+	if got, want := clone.Config(), parent.Config(); got != want {
+		t.Errorf("Clone().Config() = %+v, want %+v", got, want)
+	}
+	if got := clone.Stats(); got != (Stats{}) {
+		t.Errorf("Clone().Stats() = %+v, want zero", got)
+	}
+
+	// the clone enforces the same cap, counting on its own
+	large := clone.Get()
+	large.Grow(128)
+	clone.Release(large)
+	clone.Release(clone.Get())
+
+	if got := clone.Stats(); got.Gets != 2 || got.Releases != 2 || got.Discards != 1 {
+		t.Errorf("Clone().Stats() = %+v, want 2 Gets, 2 Releases and 1 Discard", got)
+	}
+	if got := parent.Stats(); got.Gets != 1 || got.Releases != 1 || got.Discards != 0 {
+		t.Errorf("parent Stats() = %+v, want 1 Get, 1 Release and no Discards", got)
+	}
+	if got := clone.adaptive.learned(); got < len("parent") {
+		t.Errorf("Clone() learned size = %d, want >= %d", got, len("parent"))
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	var p StringPool
+
+	for i := 0; i < 3; i++ {
+		sb := p.Get()
+		if sb.Len() != 0 {
+			t.Fatalf("round %d: Get() returned a builder holding %q", i, sb.String())
+		}
+		sb.WriteString("hello")
+		if got := p.ReleaseString(sb); got != "hello" {
+			t.Errorf("round %d: ReleaseString() = %q, want %q", i, got, "hello")
+		}
+	}
+
+	if got := p.Stats(); got.Gets != 3 || got.Releases != 3 || got.Misses < 1 {
+		t.Errorf("Stats() = %+v, want 3 Gets, 3 Releases and at least 1 Miss", got)
+	}
+	if got, want := p.Config(), New().Config(); got != want {
+		t.Errorf("zero value Config() = %+v, want %+v as from New()", got, want)
+	}
+	if got := p.RetainedBytes(); got != 0 {
+		t.Errorf("zero value RetainedBytes() = %d, want 0", got)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	tests := []struct {
+		name string
+		pool *StringPool
+	}{
+		{"new", New()},
+		{"zero value", &StringPool{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.pool
+			sbs := make([]*strings.Builder, 4)
+			for i := range sbs {
+				sbs[i] = p.Get()
+			}
+			held := p.Get()
+			for _, sb := range sbs {
+				p.Release(sb)
+			}
+
+			p.Drain()
+			if got := p.RetainedBytes(); got != 0 {
+				t.Errorf("RetainedBytes() after Drain() = %d, want 0", got)
+			}
+
+			before := p.Stats().Misses
+			for range sbs {
+				p.Get()
+			}
+			if got := p.Stats().Misses - before; got != uint64(len(sbs)) {
+				t.Errorf("Gets after Drain() missed %d times, want %d", got, len(sbs))
+			}
+
+			// builders handed out before Drain may still be released
+			held.WriteString("in flight")
+			p.Release(held)
+			if got := p.Get(); got.Len() != 0 {
+				t.Errorf("Get() after releasing an in-flight builder returned %q", got.String())
+			}
+		})
+	}
+}
+
+func TestGetWithCapacity(t *testing.T) {
+	tests := []struct {
+		name string
+		pool *StringPool
+		n    int
+	}{
+		{"global", nil, 4096},
+		{"newPool", New(), 8192},
+		{"zero", New(), 0},
+		{"negative", New(), -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb *strings.Builder
+			if tt.pool == nil {
+				sb = GetWithCapacity(tt.n)
+				defer Release(sb)
+			} else {
+				sb = tt.pool.GetWithCapacity(tt.n)
+				defer tt.pool.Release(sb)
+			}
+
+			if sb.Len() != 0 {
+				t.Errorf("GetWithCapacity(%d).Len() = %d, want 0", tt.n, sb.Len())
+			}
+			if sb.Cap() < tt.n {
+				t.Errorf("GetWithCapacity(%d).Cap() = %d, want >= %d", tt.n, sb.Cap(), tt.n)
+			}
+			if tt.n <= 0 && sb.Cap() != 0 {
+				t.Errorf("GetWithCapacity(%d).Cap() = %d, want 0 as from Get", tt.n, sb.Cap())
+			}
+		})
+	}
+}
+
+func TestGetWithCapacityNotRetained(t *testing.T) {
+	p := New()
+	sb := p.GetWithCapacity(4096)
+	p.Release(sb)
+
+	if got := p.Get().Cap(); got != 0 {
+		t.Errorf("Get().Cap() after releasing a grown builder = %d, want 0", got)
+	}
+}
+
+func BenchmarkGetWithCapacity(b *testing.B) {
+	doc := strings.Repeat("x", 64)
+
+	b.Run("Get", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sb := Get()
+			for j := 0; j < 96; j++ {
+				sb.WriteString(doc)
+			}
+			out = sb.String()
+			Release(sb)
+		}
+	})
+	b.Run("GetWithCapacity", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sb := GetWithCapacity(96 * len(doc))
+			for j := 0; j < 96; j++ {
+				sb.WriteString(doc)
+			}
+			out = sb.String()
+			Release(sb)
+		}
+	})
+}
+
+func TestReleaseString(t *testing.T) {
+	tests := []struct {
+		name string
+		pool *StringPool
+		in   string
+	}{
+		{"global", nil, "hello"},
+		{"empty", New(), ""},
+		{"pool", New(), "héllo, 世界"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.pool
+			if p == nil {
+				p = global
+			}
+			before := p.Stats()
+
+			sb := p.Get()
+			sb.WriteString(tt.in)
+			var got string
+			if tt.pool == nil {
+				got = ReleaseString(sb)
+			} else {
+				got = tt.pool.ReleaseString(sb)
+			}
+
+			if got != tt.in {
+				t.Errorf("ReleaseString() = %q, want %q", got, tt.in)
+			}
+			if sb.Len() != 0 {
+				t.Errorf("ReleaseString() left Len() = %d, want 0", sb.Len())
+			}
+			after := p.Stats()
+			if after.Releases-before.Releases != 1 || after.Discards != before.Discards {
+				t.Errorf("ReleaseString() did not park the builder: before %+v, after %+v", before, after)
+			}
+		})
+	}
+}
+
+func TestReleaseForeign(t *testing.T) {
+	tests := []struct {
+		name        string
+		grow        int
+		wantParked  bool
+		wantDiscard uint64
+	}{
+		{"small", 0, true, 0},
+		{"over cap", 256, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(WithMaxRetainedCap(128))
+
+			sb := &strings.Builder{}
+			sb.Grow(tt.grow)
+			sb.WriteString("made before the pool")
+			p.Release(sb)
+
+			if sb.Len() != 0 {
+				t.Errorf("released builder Len() = %d, want 0", sb.Len())
+			}
+			s := p.Stats()
+			if s.Releases != 1 || s.Discards != tt.wantDiscard {
+				t.Errorf("Stats() = %+v, want 1 Release and %d Discards", s, tt.wantDiscard)
+			}
+			if parked := p.RetainedBytes() > 0; parked != tt.wantParked {
+				t.Errorf("RetainedBytes() = %d, want parked %v", p.RetainedBytes(), tt.wantParked)
+			}
+
+			// a sync.Pool may drop the builder, so a Get need
+			// not return it; if it does, it must be empty
+			got := p.Get()
+			if got == sb && !tt.wantParked {
+				t.Errorf("Get() returned a discarded builder")
+			}
+			if got.Len() != 0 {
+				t.Errorf("Get() Len() = %d, want 0", got.Len())
+			}
+		})
+	}
+}
+
+func TestAdopt(t *testing.T) {
+	tests := []struct {
+		name string
+		pool *StringPool
+	}{
+		{"global", global},
+		{"newPool", New()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			tt.pool.Adopt(sb)
+
+			sb.WriteString("adopted")
+			if got := sb.String(); got != "adopted" {
+				t.Errorf("adopted builder String() = %q, want %q", got, "adopted")
+			}
+
+			tt.pool.Release(sb)
+			if got := sb.Len(); got != 0 {
+				t.Errorf("released builder Len() = %v, want %v", got, 0)
+			}
+		})
+	}
+
+	// nil builders are ignored
+	Adopt(nil)
+}
+
+func TestGetReusable(t *testing.T) {
+	p := New()
+	sb, reset := p.GetReusable()
+
+	words := []string{"alpha", "beta", "", "gamma"}
+	got := make([]string, 0, len(words))
+	for _, w := range words {
+		reset()
+		sb.WriteString(w)
+		got = append(got, sb.String())
+	}
+	p.Release(sb)
+
+	if !reflect.DeepEqual(got, words) {
+		t.Errorf("GetReusable() built %q, want %q", got, words)
+	}
+	if s := p.Stats(); s.Gets != 1 || s.Releases != 1 {
+		t.Errorf("GetReusable() loop made %d Gets and %d Releases, want 1 and 1", s.Gets, s.Releases)
+	}
+}
+
+func BenchmarkGetReusable(b *testing.B) {
+	const words = 64
+	b.Run("Get/Release", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < words; j++ {
+				sb := Get()
+				sb.WriteString("stringpool")
+				out = sb.String()
+				Release(sb)
+			}
+		}
+	})
+	b.Run("GetReusable", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sb, reset := GetReusable()
+			for j := 0; j < words; j++ {
+				reset()
+				sb.WriteString("stringpool")
+				out = sb.String()
+			}
+			Release(sb)
+		}
+	})
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpooltest
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/skeptycal/stringpool"
+)
+
+// Limits on the number of scaling factors (powers of 2) of a
+// benchmark set.
+const (
+	// DefaultMaxScalingFactor is used for an out of range
+	// maxScale.
+	DefaultMaxScalingFactor = 6
+
+	// MaxScalingFactor is the ceiling of NewBenchmarkSet.
+	MaxScalingFactor = 10
+
+	// ScalingCeilingLimit is the highest ceiling accepted by
+	// NewBenchmarkSetCeiling. At this scale a single
+	// iteration writes 255 << 23 bytes, about 2 GiB.
+	ScalingCeilingLimit = 24
+)
+
+// benchSink receives benchmark results so that the compiler
+// cannot optimize the work away.
+var benchSink string
+
+// parallelSink is the benchSink of parallel benchmarks; each
+// goroutine adds the length of its last result once, so the
+// goroutines do not race on a shared string.
+var parallelSink atomic.Int64
+
+// Benchmark is a single entry of a benchmark set: a Pooler to
+// measure under a name.
+type Benchmark struct {
+	Name string
+	Pool stringpool.Pooler
+}
+
+// run measures one Get, 255 * scale single byte writes, a
+// String and a Release per iteration.
+func (bm *Benchmark) run(b *testing.B, scale int) {
+	for i := 0; i < b.N; i++ {
+		benchSink = bm.build(scale)
+	}
+}
+
+// runParallel measures the same iterations as run, spread
+// over GOMAXPROCS goroutines that share the pool, so that it
+// reports contention rather than single-threaded throughput.
+func (bm *Benchmark) runParallel(b *testing.B, scale int) {
+	b.RunParallel(func(pb *testing.PB) {
+		var s string
+		for pb.Next() {
+			s = bm.build(scale)
+		}
+		parallelSink.Add(int64(len(s)))
+	})
+}
+
+// build is a single iteration of run and runParallel.
+func (bm *Benchmark) build(scale int) string {
+	sb := bm.Pool.Get()
+	for k := 0; k < 255; k++ {
+		for l := 0; l < scale; l++ {
+			sb.WriteByte(byte(k))
+		}
+	}
+	s := sb.String()
+	bm.Pool.Release(sb)
+	return s
+}
+
+// BenchmarkResult is the measurement of a Benchmark at one
+// scaling factor, as made by Report. The embedded
+// testing.BenchmarkResult provides NsPerOp, AllocsPerOp and
+// the like.
+type BenchmarkResult struct {
+	Name  string
+	Scale int
+	testing.BenchmarkResult
+}
+
+// Benchmarks drives a set of pool benchmarks over a range of
+// scaling factors. For each scaling factor in turn, Next
+// returns every Benchmark of the set once:
+//
+//	set := NewBenchmarkSet(2, a, b)
+//	for bm := set.Next(); bm != nil; bm = set.Next() {
+//		// a, b at Scale() 1, then a, b at Scale() 2
+//	}
+//
+// Run does the same as sub-benchmarks of a testing.B, and
+// RunParallel as parallel sub-benchmarks. A set is used once.
+type Benchmarks interface {
+	// Next returns the next benchmark to run, or nil when
+	// the set is exhausted.
+	Next() *Benchmark
+
+	// Scale returns the scaling factor of the benchmark
+	// last returned by Next.
+	Scale() int
+
+	// Count returns the number of benchmarks returned by
+	// Next so far.
+	Count() int
+
+	// Setup prepares the set. Next calls it before returning
+	// the first benchmark; it runs at most once per set.
+	Setup() error
+
+	// Cleanup tears the set down, after which Next returns
+	// nil. Next calls it once the set is exhausted; it runs
+	// at most once per set, and only after a successful
+	// Setup.
+	Cleanup() error
+
+	// Err returns the first error returned by Setup or
+	// Cleanup.
+	Err() error
+
+	// Run runs the rest of the set as sub-benchmarks of b.
+	Run(b *testing.B)
+
+	// RunParallel is like Run, but each sub-benchmark runs
+	// its iterations on parallel goroutines with
+	// b.RunParallel. The Poolers of the set must be safe for
+	// concurrent use.
+	RunParallel(b *testing.B)
+
+	// Report runs the rest of the set with testing.Benchmark
+	// and returns the results in the order run, so that they
+	// can be formatted or asserted on outside of go test
+	// -bench, e.g. that a pool allocates less than no pool.
+	// The error is that of Err.
+	Report() ([]BenchmarkResult, error)
+}
+
+type (
+	setupFunc   func(set *benchmarkSet) error
+	cleanupFunc func(set *benchmarkSet) error
+)
+
+func defaultSetup(set *benchmarkSet) error { return nil }
+
+// defaultCleanup resets the set's counters and drops its
+// benchmarks, releasing the pools they refer to.
+func defaultCleanup(set *benchmarkSet) error {
+	set.runs = nil
+	set.scale, set.run, set.cur, set.count = 0, 0, 0, 0
+	return nil
+}
+
+// benchmarkSet implements Benchmarks.
+type benchmarkSet struct {
+	runs     []Benchmark
+	maxScale int // number of scaling factors, 1 << 0 up to 1 << (maxScale-1)
+
+	scale int // scaling exponent of the next benchmark
+	run   int // index of the next benchmark in runs
+	cur   int // scaling exponent of the last benchmark returned
+	count int
+
+	setup     setupFunc
+	cleanup   cleanupFunc
+	setUp     bool
+	cleanedUp bool
+	err       error
+}
+
+// NewBenchmarkSet returns a set that runs each of runs at the
+// scaling factors 1, 2, 4, ... 1 << (maxScale-1). A maxScale
+// outside 1 to MaxScalingFactor is replaced by
+// DefaultMaxScalingFactor.
+func NewBenchmarkSet(maxScale int, runs ...Benchmark) Benchmarks {
+	return NewBenchmarkSetCeiling(MaxScalingFactor, maxScale, runs...)
+}
+
+// NewBenchmarkSetCeiling is like NewBenchmarkSet, but accepts
+// a maxScale up to ceiling rather than MaxScalingFactor, to
+// stress very large builders. A maxScale outside 1 to
+// ceiling is still replaced by DefaultMaxScalingFactor, or
+// by ceiling if that is lower. The ceiling itself is limited
+// to 1 to ScalingCeilingLimit; a ceiling below 1 means
+// MaxScalingFactor.
+func NewBenchmarkSetCeiling(ceiling, maxScale int, runs ...Benchmark) Benchmarks {
+	switch {
+	case ceiling < 1:
+		ceiling = MaxScalingFactor
+	case ceiling > ScalingCeilingLimit:
+		ceiling = ScalingCeilingLimit
+	}
+	if maxScale < 1 || maxScale > ceiling {
+		maxScale = min(DefaultMaxScalingFactor, ceiling)
+	}
+	return newBenchmarkSet(maxScale, defaultSetup, defaultCleanup, runs...)
+}
+
+// newBenchmarkSet returns a set with the given setup and
+// cleanup funcs. maxScale is not checked.
+func newBenchmarkSet(maxScale int, setup setupFunc, cleanup cleanupFunc, runs ...Benchmark) *benchmarkSet {
+	return &benchmarkSet{
+		runs:     runs,
+		maxScale: maxScale,
+		setup:    setup,
+		cleanup:  cleanup,
+	}
+}
+
+func (s *benchmarkSet) Next() *Benchmark {
+	if s.Setup() != nil || s.cleanedUp {
+		return nil
+	}
+	if s.scale >= s.maxScale || len(s.runs) == 0 {
+		s.Cleanup()
+		return nil
+	}
+	bm := &s.runs[s.run]
+	s.cur = s.scale
+	s.count++
+
+	s.run++
+	if s.run == len(s.runs) {
+		s.run = 0
+		s.scale++
+	}
+	return bm
+}
+
+func (s *benchmarkSet) Scale() int {
+	return 1 << s.cur
+}
+
+func (s *benchmarkSet) Count() int {
+	return s.count
+}
+
+func (s *benchmarkSet) Setup() error {
+	if !s.setUp {
+		s.setUp = true
+		if err := s.setup(s); err != nil {
+			// nothing was set up, so there is nothing to clean up
+			s.setErr(err)
+			s.cleanedUp = true
+		}
+	}
+	return s.err
+}
+
+func (s *benchmarkSet) Cleanup() error {
+	if s.setUp && !s.cleanedUp {
+		s.cleanedUp = true
+		s.setErr(s.cleanup(s))
+	}
+	return s.err
+}
+
+func (s *benchmarkSet) Err() error {
+	return s.err
+}
+
+// setErr records err unless an earlier error was recorded.
+func (s *benchmarkSet) setErr(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *benchmarkSet) Run(b *testing.B) {
+	s.runAll(b, (*Benchmark).run)
+}
+
+func (s *benchmarkSet) RunParallel(b *testing.B) {
+	s.runAll(b, (*Benchmark).runParallel)
+}
+
+func (s *benchmarkSet) Report() ([]BenchmarkResult, error) {
+	var results []BenchmarkResult
+	for bm := s.Next(); bm != nil; bm = s.Next() {
+		scale := s.Scale()
+		r := testing.Benchmark(func(b *testing.B) {
+			bm.run(b, scale)
+		})
+		results = append(results, BenchmarkResult{bm.Name, scale, r})
+	}
+	return results, s.Err()
+}
+
+// runAll runs the rest of the set as sub-benchmarks of b,
+// measuring each with run.
+func (s *benchmarkSet) runAll(b *testing.B, run func(bm *Benchmark, b *testing.B, scale int)) {
+	for bm := s.Next(); bm != nil; bm = s.Next() {
+		scale := s.Scale()
+		b.Run(bm.Name+"("+strconv.Itoa(scale)+")", func(b *testing.B) {
+			run(bm, b, scale)
+		})
+	}
+	if err := s.Err(); err != nil {
+		b.Fatal(err)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpooltest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/skeptycal/stringpool"
+)
+
+func BenchmarkStringPool(b *testing.B) {
+	NewBenchmarkSet(DefaultMaxScalingFactor,
+		Benchmark{"default", stringpool.Default()},
+		Benchmark{"newPool", stringpool.New()},
+		Benchmark{"NoPool", stringpool.NoPool{}},
+	).Run(b)
+}
+
+// BenchmarkStringPoolParallel measures the pools of
+// BenchmarkStringPool under contention. Every goroutine does
+// its own Get, writes and Release, and no package variable
+// is shared, so it also runs clean with -race.
+func BenchmarkStringPoolParallel(b *testing.B) {
+	NewBenchmarkSet(DefaultMaxScalingFactor,
+		Benchmark{"default", stringpool.Default()},
+		Benchmark{"newPool", stringpool.New()},
+		Benchmark{"NoPool", stringpool.NoPool{}},
+	).RunParallel(b)
+}
+
+func TestBenchmarkSet(t *testing.T) {
+	type step struct {
+		Name  string
+		Scale int
+		Count int
+	}
+	tests := []struct {
+		name     string
+		maxScale int
+		runs     []Benchmark
+		want     []step
+	}{
+		{"empty", 2, nil, nil},
+		{
+			"two runs",
+			2,
+			[]Benchmark{{"a", stringpool.New()}, {"b", stringpool.NoPool{}}},
+			[]step{{"a", 1, 1}, {"b", 1, 2}, {"a", 2, 3}, {"b", 2, 4}},
+		},
+		{
+			"three scales",
+			3,
+			[]Benchmark{{"a", stringpool.New()}},
+			[]step{{"a", 1, 1}, {"a", 2, 2}, {"a", 4, 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := NewBenchmarkSet(tt.maxScale, tt.runs...)
+
+			var got []step
+			for bm := set.Next(); bm != nil; bm = set.Next() {
+				got = append(got, step{bm.Name, set.Scale(), set.Count()})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("benchmark sequence = %+v, want %+v", got, tt.want)
+			}
+			if bm := set.Next(); bm != nil {
+				t.Errorf("Next() after exhaustion = %+v, want nil", bm)
+			}
+		})
+	}
+}
+
+func TestBenchmarkSetScaleClamp(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxScale int
+		want     int
+	}{
+		{"zero", 0, DefaultMaxScalingFactor},
+		{"negative", -3, DefaultMaxScalingFactor},
+		{"too large", MaxScalingFactor + 1, DefaultMaxScalingFactor},
+		{"min", 1, 1},
+		{"max", MaxScalingFactor, MaxScalingFactor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := NewBenchmarkSet(tt.maxScale, Benchmark{"a", stringpool.NoPool{}})
+			n := 0
+			for set.Next() != nil {
+				n++
+			}
+			if n != tt.want {
+				t.Errorf("NewBenchmarkSet(%d) ran %d scales, want %d", tt.maxScale, n, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBenchmarkSetCeiling(t *testing.T) {
+	tests := []struct {
+		name     string
+		ceiling  int
+		maxScale int
+		wantTop  int
+	}{
+		{"above default ceiling", 16, 14, 1 << 13},
+		{"at ceiling", 12, 12, 1 << 11},
+		{"above ceiling", 12, 13, 1 << (DefaultMaxScalingFactor - 1)},
+		{"out of range", 16, 0, 1 << (DefaultMaxScalingFactor - 1)},
+		{"ceiling limited", 40, 30, 1 << (DefaultMaxScalingFactor - 1)},
+		{"low ceiling", 4, 4, 1 << 3},
+		{"low ceiling out of range", 4, 9, 1 << 3},
+		{"no ceiling", 0, 9, 1 << 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := NewBenchmarkSetCeiling(tt.ceiling, tt.maxScale, Benchmark{"a", stringpool.NoPool{}})
+
+			top := 0
+			for bm := set.Next(); bm != nil; bm = set.Next() {
+				if s := set.Scale(); s > top {
+					top = s
+				}
+			}
+			if top != tt.wantTop {
+				t.Errorf("NewBenchmarkSetCeiling(%d, %d) top Scale() = %d, want %d", tt.ceiling, tt.maxScale, top, tt.wantTop)
+			}
+		})
+	}
+}
+
+func TestBenchmarkSetSetupCleanup(t *testing.T) {
+	errSetup := errors.New("setup failed")
+	errCleanup := errors.New("cleanup failed")
+
+	tests := []struct {
+		name         string
+		setupErr     error
+		cleanupErr   error
+		wantRuns     int
+		wantCleanups int
+		wantErr      error
+	}{
+		{"ok", nil, nil, 4, 1, nil},
+		{"setup error", errSetup, nil, 0, 0, errSetup},
+		{"cleanup error", nil, errCleanup, 4, 1, errCleanup},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var setups, cleanups, runsAtSetup int
+			set := newBenchmarkSet(2,
+				func(set *benchmarkSet) error {
+					setups++
+					runsAtSetup = set.Count()
+					return tt.setupErr
+				},
+				func(set *benchmarkSet) error {
+					cleanups++
+					return defaultCleanup(set)
+				},
+				Benchmark{"a", stringpool.New()}, Benchmark{"b", stringpool.NoPool{}},
+			)
+			if tt.cleanupErr != nil {
+				cleanup := set.cleanup
+				set.cleanup = func(set *benchmarkSet) error {
+					cleanup(set)
+					return tt.cleanupErr
+				}
+			}
+
+			runs := 0
+			for bm := set.Next(); bm != nil; bm = set.Next() {
+				runs++
+			}
+			// further calls must not repeat setup or cleanup
+			set.Next()
+			set.Setup()
+			set.Cleanup()
+
+			if setups != 1 || runsAtSetup != 0 {
+				t.Errorf("setup ran %d times after %d runs, want once before the first", setups, runsAtSetup)
+			}
+			if cleanups != tt.wantCleanups {
+				t.Errorf("cleanup ran %d times, want %d", cleanups, tt.wantCleanups)
+			}
+			if runs != tt.wantRuns {
+				t.Errorf("Next() returned %d benchmarks, want %d", runs, tt.wantRuns)
+			}
+			if err := set.Err(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Err() = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantCleanups > 0 && (set.Count() != 0 || set.runs != nil) {
+				t.Errorf("defaultCleanup left Count() = %d, runs = %v", set.Count(), set.runs)
+			}
+		})
+	}
+}
+
+func TestBenchmarkSetReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs testing.Benchmark")
+	}
+	results, err := NewBenchmarkSet(1,
+		Benchmark{"pool", stringpool.New()},
+		Benchmark{"non-pool", stringpool.NoPool{}},
+	).Report()
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Name)
+		if r.Scale != 1 || r.N == 0 {
+			t.Errorf("Report() %s: Scale = %d, N = %d, want 1 and > 0", r.Name, r.Scale, r.N)
+		}
+	}
+	if want := []string{"pool", "non-pool"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Report() names = %v, want %v", names, want)
+	}
+	if pool, non := results[0].AllocsPerOp(), results[1].AllocsPerOp(); pool >= non {
+		t.Errorf("pool AllocsPerOp() = %d, want fewer than non-pool %d", pool, non)
+	}
+
+	setupErr := errors.New("setup failed")
+	set := newBenchmarkSet(1, func(*benchmarkSet) error { return setupErr }, defaultCleanup, Benchmark{"a", stringpool.New()})
+	if results, err := set.Report(); len(results) != 0 || !errors.Is(err, setupErr) {
+		t.Errorf("Report() after failed setup = %v, %v, want none and %v", results, err, setupErr)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+// Package stringpooltest provides helpers for testing and
+// benchmarking code that uses stringpool pools.
+package stringpooltest
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/skeptycal/stringpool"
+)
+
+const (
+	// exclusiveRounds is the number of Get/write/verify/Release
+	// cycles run by each CheckExclusive goroutine.
+	exclusiveRounds = 500
+
+	// exclusiveWrites is the number of times each goroutine
+	// writes its marker into a builder per cycle.
+	exclusiveWrites = 8
+)
+
+// CheckExclusive verifies that p never hands the same builder
+// to two goroutines at once. It runs a burst of concurrent
+// Get/write/verify/Release cycles in which each goroutine
+// writes a marker unique to it, yields, and then checks that
+// its builder holds nothing but its own markers. Any builder
+// that is not empty when handed out, that is observed in use
+// by another goroutine, or that contains another goroutine's
+// marker fails the test.
+//
+// Other projects may call it from their own tests to
+// validate their pool usage. Run under the race detector
+// (go test -race), the burst also reports any unsynchronized
+// access to builders.
+func CheckExclusive(t testing.TB, p *stringpool.StringPool) {
+	t.Helper()
+
+	workers := 4 * runtime.GOMAXPROCS(0)
+
+	var (
+		owners sync.Map // *strings.Builder -> worker id
+		wg     sync.WaitGroup
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(id int) {
+			defer wg.Done()
+
+			marker := "<" + strconv.Itoa(id) + ">"
+			want := ""
+			for i := 0; i < exclusiveWrites; i++ {
+				want += marker
+			}
+
+			for i := 0; i < exclusiveRounds; i++ {
+				sb := p.Get()
+				if other, loaded := owners.LoadOrStore(sb, id); loaded {
+					t.Errorf("stringpool: builder %p handed to worker %d while held by worker %v", sb, id, other)
+					return
+				}
+				if sb.Len() != 0 {
+					t.Errorf("stringpool: worker %d got a non-empty builder: %q", id, sb.String())
+				}
+
+				for j := 0; j < exclusiveWrites; j++ {
+					sb.WriteString(marker)
+					runtime.Gosched()
+				}
+				if got := sb.String(); got != want {
+					t.Errorf("stringpool: worker %d builder = %q, want %q", id, got, want)
+				}
+
+				owners.Delete(sb)
+				p.Release(sb)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
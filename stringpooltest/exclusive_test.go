@@ -0,0 +1,26 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpooltest
+
+import (
+	"testing"
+
+	"github.com/skeptycal/stringpool"
+)
+
+func TestCheckExclusive(t *testing.T) {
+	tests := []struct {
+		name string
+		pool *stringpool.StringPool
+	}{
+		{"default", stringpool.Default()},
+		{"newPool", stringpool.New()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			CheckExclusive(t, tt.pool)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// ReplaceFirst returns a copy of s with the first instance
+// of old replaced by new. The result is built in a pooled
+// strings.Builder sized to the exact result length.
+//
+// If old is not found, s is returned unchanged and nothing
+// is built. If old is empty, new is inserted at the
+// beginning of s, matching strings.Replace(s, old, new, 1).
+func ReplaceFirst(s, old, new string) string {
+	i := strings.Index(s, old)
+	if i < 0 {
+		return s
+	}
+
+	sb := Get()
+	defer Release(sb)
+
+	sb.Grow(len(s) - len(old) + len(new))
+	sb.WriteString(s[:i])
+	sb.WriteString(new)
+	sb.WriteString(s[i+len(old):])
+	return sb.String()
+}
+
+// Join concatenates parts, placing sep between consecutive
+// elements, as strings.Join does. The result is built in a
+// builder from the global pool, grown once to the exact
+// result length. Zero parts give the empty string and a
+// single part is returned as is, without building.
+func Join(sep string, parts ...string) string {
+	return Default().Join(sep, parts...)
+}
+
+// Join concatenates parts, placing sep between consecutive
+// elements, in a builder from the pool. See the package
+// level Join for details.
+func (bp *StringPool) Join(sep string, parts ...string) string {
+	switch len(parts) {
+	case 0:
+		return ""
+	case 1:
+		return parts[0]
+	}
+
+	n := len(sep) * (len(parts) - 1)
+	for _, p := range parts {
+		n += len(p)
+	}
+
+	sb := bp.Get()
+	defer bp.Release(sb)
+
+	sb.Grow(n)
+	sb.WriteString(parts[0])
+	for _, p := range parts[1:] {
+		sb.WriteString(sep)
+		sb.WriteString(p)
+	}
+	return sb.String()
+}
+
+// WriteRepeat writes count copies of s into sb, like
+// sb.WriteString(strings.Repeat(s, count)) without the
+// intermediate string. sb is grown once, and the copies are
+// made by doubling what has been written, so even a single
+// byte repeated many times takes only a few copies.
+//
+// A count of zero writes nothing. A negative count, or one
+// whose result would not fit in an int, is misuse and is
+// handled according to SetStrictMode by writing nothing.
+func WriteRepeat(sb *strings.Builder, s string, count int) {
+	switch {
+	case count < 0:
+		misuse("WriteRepeat: negative count %d", count)
+		return
+	case count == 0 || len(s) == 0:
+		return
+	case len(s) > math.MaxInt/count:
+		misuse("WriteRepeat: %d copies of %d bytes overflow", count, len(s))
+		return
+	}
+
+	n := len(s) * count
+	sb.Grow(n)
+	start := sb.Len()
+	sb.WriteString(s)
+	for written := len(s); written < n; {
+		// the bytes written so far are in sb's buffer, which
+		// Grow made large enough not to move
+		chunk := sb.String()[start : start+min(written, n-written)]
+		sb.WriteString(chunk)
+		written += len(chunk)
+	}
+}
+
+// Repeat returns count copies of s, like strings.Repeat,
+// built in a builder from the global pool. See WriteRepeat
+// for the handling of count.
+func Repeat(s string, count int) string {
+	return Default().Repeat(s, count)
+}
+
+// Repeat returns count copies of s built in a builder from
+// the pool. See WriteRepeat for the handling of count.
+func (bp *StringPool) Repeat(s string, count int) string {
+	sb := bp.Get()
+	defer bp.Release(sb)
+
+	WriteRepeat(sb, s, count)
+	return sb.String()
+}
+
+// WriteLines writes each of lines into sb followed by a
+// newline, growing sb once by the exact total. Every line is
+// terminated, including the last, so that further lines may
+// be appended; no lines write nothing.
+func WriteLines(sb *strings.Builder, lines []string) {
+	n := len(lines)
+	for _, l := range lines {
+		n += len(l)
+	}
+	sb.Grow(n)
+	for _, l := range lines {
+		sb.WriteString(l)
+		sb.WriteByte('\n')
+	}
+}
+
+// JoinLines returns lines joined into one string, each line
+// terminated by a newline, built in a builder from the
+// global pool. See WriteLines.
+func JoinLines(lines []string) string {
+	return Default().JoinLines(lines)
+}
+
+// JoinLines returns lines joined into one string, each line
+// terminated by a newline, built in a builder from the pool.
+// It equals strings.Join(lines, "\n") + "\n" except that no
+// lines give the empty string.
+func (bp *StringPool) JoinLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	sb := bp.Get()
+	defer bp.Release(sb)
+
+	WriteLines(sb, lines)
+	return sb.String()
+}
+
+// AlignedKV formats pairs of keys and values as lines of
+// the form "key: value", padding each key with spaces so
+// that the colons line up:
+//
+//	name   : stringpool
+//	version: 1.0
+//
+// Widths are measured in runes. Each line ends with a
+// newline. Empty input returns the empty string. The
+// result is built in a pooled strings.Builder.
+func AlignedKV(pairs [][2]string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	width, size := 0, 0
+	for _, kv := range pairs {
+		if n := utf8.RuneCountInString(kv[0]); n > width {
+			width = n
+		}
+		size += len(kv[1]) + 3
+	}
+
+	sb := Get()
+	defer Release(sb)
+
+	sb.Grow(size + len(pairs)*width)
+	for _, kv := range pairs {
+		sb.WriteString(kv[0])
+		for n := utf8.RuneCountInString(kv[0]); n < width; n++ {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(": ")
+		sb.WriteString(kv[1])
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"crypto/sha256"
+	"io"
+	"strings"
+)
+
+// tagBytes is the number of leading SHA-256 bytes used in
+// the tag returned by BuildTagged.
+const tagBytes = 8
+
+// BuildTagged calls fn to build content in a builder from
+// the global pool and returns the content together with a
+// short tag: the first 8 bytes of its SHA-256 hash as 16
+// lowercase hex digits. The tag is suitable for cache
+// busting asset names, e.g. "app." + tag + ".js".
+//
+// Both the content and the hex tag are built in pooled
+// builders.
+func BuildTagged(fn func(sb *strings.Builder)) (content string, tag string) {
+	sb := Get()
+	defer Release(sb)
+
+	fn(sb)
+	content = sb.String()
+
+	h := sha256.New()
+	io.WriteString(h, content)
+	var buf [sha256.Size]byte
+	sum := h.Sum(buf[:0])
+
+	hb := Get()
+	defer Release(hb)
+
+	hb.Grow(2 * tagBytes)
+	for _, c := range sum[:tagBytes] {
+		hb.WriteByte(hexDigits[c>>4])
+		hb.WriteByte(hexDigits[c&0xF])
+	}
+	return content, hb.String()
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestBuildTagged(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"empty", ""},
+		{"short", "hello"},
+		{"asset", strings.Repeat("body { color: red; }\n", 100)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, tag := BuildTagged(func(sb *strings.Builder) {
+				sb.WriteString(tt.in)
+			})
+			if content != tt.in {
+				t.Errorf("BuildTagged() content = %q, want %q", content, tt.in)
+			}
+
+			sum := sha256.Sum256([]byte(tt.in))
+			if want := hex.EncodeToString(sum[:tagBytes]); tag != want {
+				t.Errorf("BuildTagged() tag = %q, want %q", tag, want)
+			}
+		})
+	}
+}
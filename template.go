@@ -0,0 +1,28 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "text/template"
+
+// ExecuteToString applies the template t to data, rendering
+// into a builder from the global pool, and returns the
+// result. It replaces the common pattern:
+//
+//	var buf bytes.Buffer
+//	err := t.Execute(&buf, data)
+//	s := buf.String()
+//
+// The builder is released whether or not execution
+// succeeds. On error, any partial output is discarded and
+// the empty string is returned along with the error.
+func ExecuteToString(t *template.Template, data interface{}) (string, error) {
+	sb := Get()
+	defer Release(sb)
+
+	if err := t.Execute(sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
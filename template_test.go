@@ -0,0 +1,45 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"testing"
+	"text/template"
+)
+
+func TestExecuteToString(t *testing.T) {
+	tests := []struct {
+		name    string
+		tmpl    string
+		data    interface{}
+		want    string
+		wantErr bool
+	}{
+		{"plain", "hello", nil, "hello", false},
+		{"field", "hello, {{.Name}}!", struct{ Name string }{"gopher"}, "hello, gopher!", false},
+		{"range", "{{range .}}[{{.}}]{{end}}", []int{1, 2, 3}, "[1][2][3]", false},
+		{"missing field", "{{.Missing}}", struct{ Name string }{"gopher"}, "", true},
+		{"call error", "before {{index . 5}}", []int{1}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New(tt.name).Parse(tt.tmpl))
+
+			before := global.Stats()
+			got, err := ExecuteToString(tmpl, tt.data)
+			after := global.Stats()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecuteToString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ExecuteToString() = %q, want %q", got, tt.want)
+			}
+			if n := after.Releases - before.Releases; n != 1 {
+				t.Errorf("ExecuteToString() made %d Releases, want 1", n)
+			}
+		})
+	}
+}
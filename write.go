@@ -0,0 +1,294 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// WriteProgressBar writes a bar such as [####----] into sb.
+// The bar has width cells between the brackets, of which
+// the leading fraction are written with filled and the
+// remainder with empty. The number of filled cells is
+// rounded down so that a bar is only full when the work
+// is complete.
+//
+// fraction is clamped to [0, 1]; NaN is treated as 0. A
+// width less than one writes an empty bar, [].
+func WriteProgressBar(sb *strings.Builder, fraction float64, width int, filled, empty byte) {
+	if width < 0 {
+		width = 0
+	}
+	switch {
+	case math.IsNaN(fraction) || fraction < 0:
+		fraction = 0
+	case fraction > 1:
+		fraction = 1
+	}
+
+	n := int(fraction * float64(width))
+
+	sb.Grow(width + 2)
+	sb.WriteByte('[')
+	for i := 0; i < width; i++ {
+		if i < n {
+			sb.WriteByte(filled)
+		} else {
+			sb.WriteByte(empty)
+		}
+	}
+	sb.WriteByte(']')
+}
+
+// indexPlaceholder is replaced by the repetition index in
+// WriteRepeatedGroup.
+const indexPlaceholder = "{i}"
+
+// WriteRepeatedGroup writes template count times into sb,
+// separated by sep. Every occurrence of the placeholder {i}
+// in template is replaced by the index of the repetition,
+// starting at zero:
+//
+//	WriteRepeatedGroup(sb, "row{i}", 3, ",")
+//	// row0,row1,row2
+//
+// This is handy for generating large, structured test
+// fixtures. A count of zero writes nothing; a negative
+// count is misuse and is handled according to
+// SetStrictMode.
+func WriteRepeatedGroup(sb *strings.Builder, template string, count int, sep string) {
+	if count < 0 {
+		misuse("WriteRepeatedGroup: negative count %d", count)
+		return
+	}
+
+	var buf [20]byte
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+		t := template
+		for {
+			j := strings.Index(t, indexPlaceholder)
+			if j < 0 {
+				break
+			}
+			sb.WriteString(t[:j])
+			sb.Write(strconv.AppendInt(buf[:0], int64(i), 10))
+			t = t[j+len(indexPlaceholder):]
+		}
+		sb.WriteString(t)
+	}
+}
+
+// WriteMap writes the entries of m into sb as key/value
+// pairs, joining each key and value with kvSep and the pairs
+// with pairSep:
+//
+//	WriteMap(sb, map[string]string{"b": "2", "a": "1"}, "&", "=")
+//	// a=1&b=2
+//
+// Keys are written in sorted order so that the output is
+// deterministic. A key or value that contains either
+// separator or a double quote is written as a Go quoted
+// string (see strconv.Quote) so that the output remains
+// unambiguous.
+func WriteMap(sb *strings.Builder, m map[string]string, pairSep, kvSep string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteString(pairSep)
+		}
+		writeMapField(sb, k, pairSep, kvSep)
+		sb.WriteString(kvSep)
+		writeMapField(sb, m[k], pairSep, kvSep)
+	}
+}
+
+// writeMapField writes s, quoting it if it contains one of
+// the separators or a double quote.
+func writeMapField(sb *strings.Builder, s, pairSep, kvSep string) {
+	if strings.IndexByte(s, '"') >= 0 ||
+		(pairSep != "" && strings.Contains(s, pairSep)) ||
+		(kvSep != "" && strings.Contains(s, kvSep)) {
+		var buf [64]byte
+		sb.Write(strconv.AppendQuote(buf[:0], s))
+		return
+	}
+	sb.WriteString(s)
+}
+
+// WriteByteReplaced writes s into sb with every byte equal to
+// from replaced by to, e.g. to sanitize a separator. Writing
+// directly into the builder avoids the intermediate buffer
+// used by strings.Replacer.
+func WriteByteReplaced(sb *strings.Builder, s string, from, to byte) {
+	sb.Grow(len(s))
+	for {
+		i := strings.IndexByte(s, from)
+		if i < 0 {
+			break
+		}
+		sb.WriteString(s[:i])
+		sb.WriteByte(to)
+		s = s[i+1:]
+	}
+	sb.WriteString(s)
+}
+
+// WriteByteMapped writes s into sb with every byte b replaced
+// by table[b]. Runs of bytes that map to themselves are
+// written without modification.
+//
+// Multi-byte UTF-8 sequences are mapped byte by byte, so the
+// table should map bytes >= 0x80 to themselves unless the
+// input is known to be ASCII.
+func WriteByteMapped(sb *strings.Builder, s string, table *[256]byte) {
+	sb.Grow(len(s))
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if c := table[s[i]]; c != s[i] {
+			sb.WriteString(s[start:i])
+			sb.WriteByte(c)
+			start = i + 1
+		}
+	}
+	sb.WriteString(s[start:])
+}
+
+// WriteFields splits s around runs of white space, as
+// defined by unicode.IsSpace, rejoins the fields with sep
+// and writes the result into sb. It returns the number of
+// fields written.
+//
+// The effect is that of
+//
+//	sb.WriteString(strings.Join(strings.Fields(s), string(sep)))
+//
+// done in a single pass without allocating the intermediate
+// slice of fields.
+func WriteFields(sb *strings.Builder, s string, sep byte) int {
+	n := 0
+	start := -1 // start of the current field, or -1
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				sb.WriteString(s[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			if n > 0 {
+				sb.WriteByte(sep)
+			}
+			n++
+			start = i
+		}
+	}
+	if start >= 0 {
+		sb.WriteString(s[start:])
+	}
+	return n
+}
+
+// WriteAll writes each of args into sb, without separators,
+// using the most efficient path for its type:
+//
+//   - string and []byte are written directly;
+//   - integers, floats and booleans are formatted with
+//     strconv into a stack buffer (floats as with %v);
+//   - error and fmt.Stringer values are written using their
+//     Error and String methods, except nil pointers, which
+//     are written as fmt writes them;
+//   - anything else is written with fmt.Fprint.
+//
+// It is an allocation-conscious alternative to fmt.Fprint.
+// Unlike fmt.Fprint, it never adds spaces between operands.
+func WriteAll(sb *strings.Builder, args ...interface{}) {
+	// grow once up front; strings and byte slices are sized
+	// exactly and other values are given a typical width
+	n := 0
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			n += len(v)
+		case []byte:
+			n += len(v)
+		default:
+			n += 8
+		}
+	}
+	sb.Grow(n)
+
+	var buf [32]byte
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			sb.WriteString(v)
+		case []byte:
+			sb.Write(v)
+		case int:
+			sb.Write(strconv.AppendInt(buf[:0], int64(v), 10))
+		case int8:
+			sb.Write(strconv.AppendInt(buf[:0], int64(v), 10))
+		case int16:
+			sb.Write(strconv.AppendInt(buf[:0], int64(v), 10))
+		case int32:
+			sb.Write(strconv.AppendInt(buf[:0], int64(v), 10))
+		case int64:
+			sb.Write(strconv.AppendInt(buf[:0], v, 10))
+		case uint:
+			sb.Write(strconv.AppendUint(buf[:0], uint64(v), 10))
+		case uint8:
+			sb.Write(strconv.AppendUint(buf[:0], uint64(v), 10))
+		case uint16:
+			sb.Write(strconv.AppendUint(buf[:0], uint64(v), 10))
+		case uint32:
+			sb.Write(strconv.AppendUint(buf[:0], uint64(v), 10))
+		case uint64:
+			sb.Write(strconv.AppendUint(buf[:0], v, 10))
+		case float32:
+			sb.Write(strconv.AppendFloat(buf[:0], float64(v), 'g', -1, 32))
+		case float64:
+			sb.Write(strconv.AppendFloat(buf[:0], v, 'g', -1, 64))
+		case bool:
+			sb.Write(strconv.AppendBool(buf[:0], v))
+		case error:
+			if isNilPointer(v) {
+				fmt.Fprint(sb, v)
+				continue
+			}
+			sb.WriteString(v.Error())
+		case fmt.Stringer:
+			if isNilPointer(v) {
+				fmt.Fprint(sb, v)
+				continue
+			}
+			sb.WriteString(v.String())
+		default:
+			fmt.Fprint(sb, v)
+		}
+	}
+}
+
+// isNilPointer reports whether v holds a nil pointer. Its
+// methods may not handle a nil receiver, so WriteAll leaves
+// such values to fmt, which recovers and prints <nil>.
+func isNilPointer(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
@@ -0,0 +1,346 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestWriteProgressBar(t *testing.T) {
+	tests := []struct {
+		name     string
+		fraction float64
+		width    int
+		want     string
+	}{
+		{"0%", 0, 8, "[--------]"},
+		{"50%", 0.5, 8, "[####----]"},
+		{"100%", 1, 8, "[########]"},
+		{"rounds down", 0.99, 8, "[#######-]"},
+		{"odd width", 0.5, 5, "[##---]"},
+		{"negative", -0.5, 4, "[----]"},
+		{"over 100%", 1.5, 4, "[####]"},
+		{"NaN", math.NaN(), 4, "[----]"},
+		{"+Inf", math.Inf(1), 4, "[####]"},
+		{"zero width", 0.5, 0, "[]"},
+		{"negative width", 0.5, -3, "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			WriteProgressBar(sb, tt.fraction, tt.width, '#', '-')
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteProgressBar() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRepeatedGroup(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		count    int
+		sep      string
+		want     string
+	}{
+		{"index", "row{i}", 3, ",", "row0,row1,row2"},
+		{"no placeholder", "ab", 3, "-", "ab-ab-ab"},
+		{"multiple placeholders", "{i}:{i}", 2, " ", "0:0 1:1"},
+		{"placeholder only", "{i}", 12, "", "01234567891011"},
+		{"single", "x{i}", 1, ",", "x0"},
+		{"zero", "x{i}", 0, ",", ""},
+		{"negative", "x{i}", -1, ",", ""},
+		{"empty sep", "<{i}>", 2, "", "<0><1>"},
+		{"newline sep", "line {i}", 2, "\n", "line 0\nline 1"},
+		{"partial brace", "{i", 2, ",", "{i,{i"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			WriteRepeatedGroup(sb, tt.template, tt.count, tt.sep)
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteRepeatedGroup() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRepeatedGroupStrict(t *testing.T) {
+	withStrictMode(true, func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("WriteRepeatedGroup() did not panic for a negative count in strict mode")
+			}
+		}()
+		WriteRepeatedGroup(&strings.Builder{}, "x", -1, "")
+	})
+}
+
+func TestWriteMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       map[string]string
+		pairSep string
+		kvSep   string
+		want    string
+	}{
+		{"nil", nil, "&", "=", ""},
+		{"empty", map[string]string{}, "&", "=", ""},
+		{"single", map[string]string{"a": "1"}, "&", "=", "a=1"},
+		{"sorted", map[string]string{"c": "3", "a": "1", "b": "2"}, "&", "=", "a=1&b=2&c=3"},
+		{"multi-byte seps", map[string]string{"b": "2", "a": "1"}, ", ", ": ", "a: 1, b: 2"},
+		{"empty value", map[string]string{"a": ""}, "&", "=", "a="},
+		{"value with pairSep", map[string]string{"a": "x&y"}, "&", "=", `a="x&y"`},
+		{"value with kvSep", map[string]string{"a": "x=y"}, "&", "=", `a="x=y"`},
+		{"key with kvSep", map[string]string{"k=v": "1"}, "&", "=", `"k=v"=1`},
+		{"value with quote", map[string]string{"a": `say "hi"`}, "&", "=", `a="say \"hi\""`},
+		{"empty seps", map[string]string{"b": "2", "a": "1"}, "", "", "a1b2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			WriteMap(sb, tt.m, tt.pairSep, tt.kvSep)
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteMap() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkWriteMap(b *testing.B) {
+	m := map[string]string{
+		"host": "localhost", "port": "8080", "user": "gopher",
+		"db": "stringpool", "sslmode": "disable", "timeout": "30s",
+	}
+	b.Run("WriteMap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sb := Get()
+			WriteMap(sb, m, " ", "=")
+			out = sb.String()
+			Release(sb)
+		}
+	})
+	b.Run("strings.Join", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			pairs := make([]string, 0, len(m))
+			for _, k := range keys {
+				pairs = append(pairs, k+"="+m[k])
+			}
+			out = strings.Join(pairs, " ")
+		}
+	})
+}
+
+// controlToSpace maps ASCII control characters to spaces
+// and every other byte to itself.
+var controlToSpace = func() *[256]byte {
+	var table [256]byte
+	for i := range table {
+		table[i] = byte(i)
+		if i < 0x20 || i == 0x7f {
+			table[i] = ' '
+		}
+	}
+	return &table
+}()
+
+func TestWriteByteReplaced(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		from, to byte
+		want     string
+	}{
+		{"empty", "", 'a', 'b', ""},
+		{"none", "hello", 'x', 'y', "hello"},
+		{"one", "a,b", ',', ';', "a;b"},
+		{"many", "a,b,,c,", ',', ' ', "a b  c "},
+		{"all", "////", '/', '\\', `\\\\`},
+		{"tab", "a\tb", '\t', ' ', "a b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			WriteByteReplaced(sb, tt.s, tt.from, tt.to)
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteByteReplaced() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteByteMapped(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"empty", "", ""},
+		{"clean", "hello, world", "hello, world"},
+		{"control chars", "a\tb\nc\rd\x00e\x1bf\x7f", "a b c d e f "},
+		{"leading and trailing", "\x01abc\x02", " abc "},
+		{"all control", "\n\n\n", "   "},
+		{"utf-8 preserved", "héllo\t世界", "héllo 世界"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			WriteByteMapped(sb, tt.s, controlToSpace)
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteByteMapped() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkWriteByteMapped(b *testing.B) {
+	s := strings.Repeat("field one\tfield two\r\n", 64)
+	replacer := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+	b.Run("WriteByteMapped", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sb := Get()
+			WriteByteMapped(sb, s, controlToSpace)
+			out = sb.String()
+			Release(sb)
+		}
+	})
+	b.Run("WriteByteReplaced", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sb := Get()
+			WriteByteReplaced(sb, s, '\t', ' ')
+			out = sb.String()
+			Release(sb)
+		}
+	})
+	b.Run("strings.Replacer", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sb := Get()
+			sb.WriteString(replacer.Replace(s))
+			out = sb.String()
+			Release(sb)
+		}
+	})
+}
+
+func TestWriteFields(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		sep  byte
+		want string
+	}{
+		{"empty", "", ' ', ""},
+		{"only spaces", "   \t\n ", ' ', ""},
+		{"single", "word", ' ', "word"},
+		{"simple", "a b c", ',', "a,b,c"},
+		{"leading", "   a b", ' ', "a b"},
+		{"trailing", "a b   ", ' ', "a b"},
+		{"multiple", "a    b\t\t c", ' ', "a b c"},
+		{"mixed whitespace", "\ta\n\vb\r\fc ", '|', "a|b|c"},
+		{"unicode space", "a b c", '-', "a-b-c"},
+		{"unicode text", " héllo  wörld ", '_', "héllo_wörld"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			n := WriteFields(sb, tt.s, tt.sep)
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteFields() wrote %q, want %q", got, tt.want)
+			}
+			if want := len(strings.Fields(tt.s)); n != want {
+				t.Errorf("WriteFields() = %v, want %v", n, want)
+			}
+			if want := strings.Join(strings.Fields(tt.s), string(tt.sep)); sb.String() != want {
+				t.Errorf("WriteFields() wrote %q, strings.Join(strings.Fields()) = %q", sb.String(), want)
+			}
+		})
+	}
+}
+
+// testStringer is a fmt.Stringer used in tests.
+type testStringer struct{ s string }
+
+func (t testStringer) String() string { return "<" + t.s + ">" }
+
+// ptrStringer and ptrError dereference their receivers, so a
+// nil pointer panics if its method is called.
+type (
+	ptrStringer struct{ s string }
+	ptrError    struct{ s string }
+)
+
+func (p *ptrStringer) String() string { return p.s }
+func (p *ptrError) Error() string     { return p.s }
+
+func TestWriteAll(t *testing.T) {
+	tests := []struct {
+		name string
+		args []interface{}
+		want string
+	}{
+		{"none", nil, ""},
+		{"string", []interface{}{"a", "b"}, "ab"},
+		{"bytes", []interface{}{[]byte("raw")}, "raw"},
+		{"ints", []interface{}{-1, int8(-8), int16(16), int32(32), int64(-64)}, "-1-81632-64"},
+		{"uints", []interface{}{uint(1), uint8(8), uint16(16), uint32(32), uint64(64)}, "18163264"},
+		{"floats", []interface{}{1.5, float32(0.25), 1e21}, "1.50.251e+21"},
+		{"bool", []interface{}{true, " ", false}, "true false"},
+		{"error", []interface{}{errors.New("boom")}, "boom"},
+		{"stringer", []interface{}{testStringer{"x"}}, "<x>"},
+		{"fallback", []interface{}{[]int{1, 2}, struct{ A int }{3}, nil}, "[1 2]{3}<nil>"},
+		{"nil stringer", []interface{}{(*ptrStringer)(nil)}, "<nil>"},
+		{"nil error", []interface{}{(*ptrError)(nil)}, "<nil>"},
+		{"pointer stringer", []interface{}{&ptrStringer{"p"}}, "p"},
+		{"mixed", []interface{}{"n=", 3, ", ok=", true}, "n=3, ok=true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			WriteAll(sb, tt.args...)
+			if got := sb.String(); got != tt.want {
+				t.Errorf("WriteAll() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkWriteAll(b *testing.B) {
+	args := []interface{}{"id=", 12345, " name=", "gopher", " score=", 98.6, " ok=", true}
+	b.Run("WriteAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sb := Get()
+			WriteAll(sb, args...)
+			out = sb.String()
+			Release(sb)
+		}
+	})
+	b.Run("fmt.Fprint", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sb := Get()
+			fmt.Fprint(sb, args...)
+			out = sb.String()
+			Release(sb)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrClosed is returned by writes to a pooled builder that
+// has already been returned to its pool.
+var ErrClosed = errors.New("stringpool: write after release")
+
+// BuildToBufio calls fn to build a string in a builder from
+// the global pool and writes the result to w, releasing the
+// builder afterwards. It returns the error, if any, from
+// writing to w.
+//
+// w is not flushed; the caller keeps control of when
+// buffered output reaches the underlying writer, e.g. once
+// at the end of a file-writing loop.
+func BuildToBufio(w *bufio.Writer, fn func(sb *strings.Builder)) error {
+	sb := Get()
+	defer Release(sb)
+
+	fn(sb)
+	_, err := w.WriteString(sb.String())
+	return err
+}
+
+// BuildTee calls fn once to build a string in a builder from
+// the global pool and writes the result to each writer in
+// ws, e.g. a log file and os.Stdout. The builder is released
+// afterwards.
+//
+// Every writer is written to even if an earlier one fails,
+// so one broken sink does not starve the others; the first
+// error encountered is returned. A writer that accepts fewer
+// bytes than given without an error yields io.ErrShortWrite.
+func BuildTee(ws []io.Writer, fn func(sb *strings.Builder)) error {
+	sb := Get()
+	defer Release(sb)
+
+	fn(sb)
+	s := sb.String()
+
+	var first error
+	for _, w := range ws {
+		n, err := io.WriteString(w, s)
+		if err == nil && n < len(s) {
+			err = io.ErrShortWrite
+		}
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Writer returns an io.Writer backed by a builder from the
+// global pool, and a finish func that returns the string
+// written and releases the builder. See StringPool.Writer.
+func Writer() (io.Writer, func() string) {
+	return Default().Writer()
+}
+
+// Writer returns an io.Writer backed by a builder from the
+// pool, and a finish func that returns the string written
+// and releases the builder. The writer may be handed to
+// json.NewEncoder, template.Execute, io.Copy and the like
+// without Get/Release bookkeeping:
+//
+//	w, finish := p.Writer()
+//	err := tmpl.Execute(w, data)
+//	s := finish()
+//
+// finish is idempotent: later calls return the same string
+// and do not release the builder again. Writes after finish
+// fail with ErrClosed. Like a strings.Builder, the writer is
+// not safe for concurrent use.
+func (bp *StringPool) Writer() (io.Writer, func() string) {
+	w := &pooledWriter{sb: bp.Get(), pool: bp}
+	return w, w.finish
+}
+
+// pooledWriter is the io.Writer returned by Writer.
+type pooledWriter struct {
+	sb   *strings.Builder // nil after finish
+	pool *StringPool
+	s    string
+}
+
+func (w *pooledWriter) Write(p []byte) (int, error) {
+	if w.sb == nil {
+		return 0, ErrClosed
+	}
+	return w.sb.Write(p)
+}
+
+func (w *pooledWriter) WriteString(s string) (int, error) {
+	if w.sb == nil {
+		return 0, ErrClosed
+	}
+	return w.sb.WriteString(s)
+}
+
+func (w *pooledWriter) finish() string {
+	if w.sb != nil {
+		w.s = w.pool.ReleaseString(w.sb)
+		w.sb = nil
+	}
+	return w.s
+}
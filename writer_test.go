@@ -0,0 +1,151 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// errWriter is an io.Writer that always fails.
+type errWriter struct{ err error }
+
+func (w errWriter) Write(p []byte) (int, error) { return 0, w.err }
+
+func TestBuildToBufio(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	for i := 0; i < 3; i++ {
+		err := BuildToBufio(w, func(sb *strings.Builder) {
+			sb.WriteString("line ")
+			sb.WriteString(SmallIntString(i))
+			sb.WriteByte('\n')
+		})
+		if err != nil {
+			t.Fatalf("BuildToBufio() error = %v, want nil", err)
+		}
+	}
+
+	if got := buf.Len(); got != 0 {
+		t.Errorf("BuildToBufio() flushed %d bytes, want 0", got)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got, want := buf.String(), "line 0\nline 1\nline 2\n"; got != want {
+		t.Errorf("BuildToBufio() wrote %q, want %q", got, want)
+	}
+}
+
+func TestBuildToBufioError(t *testing.T) {
+	want := errors.New("disk full")
+	w := bufio.NewWriterSize(errWriter{want}, 16)
+
+	// larger than the buffer, so the write reaches errWriter
+	err := BuildToBufio(w, func(sb *strings.Builder) {
+		sb.WriteString(strings.Repeat("x", 64))
+	})
+	if !errors.Is(err, want) {
+		t.Errorf("BuildToBufio() error = %v, want %v", err, want)
+	}
+}
+
+func TestBuildTee(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	tests := []struct {
+		name    string
+		fail    []error // one per sink; nil sinks succeed
+		wantErr error
+	}{
+		{"no sinks", nil, nil},
+		{"one sink", []error{nil}, nil},
+		{"three sinks", []error{nil, nil, nil}, nil},
+		{"middle fails", []error{nil, errFirst, nil}, errFirst},
+		{"first error wins", []error{errFirst, nil, errSecond}, errFirst},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			bufs := make([]*bytes.Buffer, len(tt.fail))
+			ws := make([]io.Writer, len(tt.fail))
+			for i, err := range tt.fail {
+				if err != nil {
+					ws[i] = errWriter{err}
+					continue
+				}
+				bufs[i] = &bytes.Buffer{}
+				ws[i] = bufs[i]
+			}
+
+			err := BuildTee(ws, func(sb *strings.Builder) {
+				calls++
+				sb.WriteString("log line\n")
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("BuildTee() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != 1 {
+				t.Errorf("BuildTee() called fn %d times, want 1", calls)
+			}
+			for i, buf := range bufs {
+				if buf == nil {
+					continue
+				}
+				if got, want := buf.String(), "log line\n"; got != want {
+					t.Errorf("BuildTee() sink %d got %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriter(t *testing.T) {
+	tmpl := template.Must(template.New("t").Parse("Hello, {{.}}!"))
+
+	p := New()
+	w, finish := p.Writer()
+	if err := tmpl.Execute(w, "world"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "Hello, world!"
+	if got := finish(); got != want {
+		t.Errorf("finish() = %q, want %q", got, want)
+	}
+	if got := finish(); got != want {
+		t.Errorf("second finish() = %q, want %q", got, want)
+	}
+	if got := p.Stats().Releases; got != 1 {
+		t.Errorf("Stats().Releases = %d, want 1", got)
+	}
+
+	if _, err := io.WriteString(w, "late"); !errors.Is(err, ErrClosed) {
+		t.Errorf("WriteString() after finish error = %v, want %v", err, ErrClosed)
+	}
+	if _, err := w.Write([]byte("late")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write() after finish error = %v, want %v", err, ErrClosed)
+	}
+	if got := finish(); got != want {
+		t.Errorf("finish() after rejected writes = %q, want %q", got, want)
+	}
+}
+
+func TestWriterGlobal(t *testing.T) {
+	w, finish := Writer()
+	if _, err := io.Copy(w, strings.NewReader("copied")); err != nil {
+		t.Fatalf("io.Copy() error = %v", err)
+	}
+	if got := finish(); got != "copied" {
+		t.Errorf("finish() = %q, want %q", got, "copied")
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// yamlIndent is the number of spaces per nesting level.
+const yamlIndent = 2
+
+// yamlLevel is one open block in a YAMLBuilder.
+type yamlLevel struct {
+	list bool // block is a sequence rather than a mapping
+	dash bool // next line opens a sequence item ("- ")
+}
+
+// YAMLBuilder emits block-style YAML into a pooled
+// strings.Builder while tracking indentation:
+//
+//	y := NewYAMLBuilder()
+//	y.Map("server")
+//	y.Map("host").Value("localhost")
+//	y.End()
+//	y.Map("tags").List()
+//	y.Value("web")
+//	y.End()
+//	s := y.String()
+//
+// produces
+//
+//	server:
+//	  host: localhost
+//	tags:
+//	  - web
+//
+// Map names the next key. A Value that follows it is
+// written on the same line; a Map or List that follows it
+// opens a nested block instead. Within a list, Value writes
+// a scalar item and Item opens a mapping item. End closes
+// the innermost block.
+//
+// Keys and values are written as plain scalars unless they
+// contain characters that are significant to YAML, in which
+// case they are double quoted.
+//
+// String returns the document and releases the builder; a
+// YAMLBuilder must not be used after String is called.
+type YAMLBuilder struct {
+	sb         *strings.Builder
+	stack      []yamlLevel
+	key        string
+	hasKey     bool
+	result     string
+	isReleased bool
+}
+
+// NewYAMLBuilder returns a YAMLBuilder backed by a builder
+// from the global pool.
+func NewYAMLBuilder() *YAMLBuilder {
+	return &YAMLBuilder{sb: Get()}
+}
+
+// Map sets the key for the next Value, or for the nested
+// mapping or list opened by the next Map or List call.
+func (y *YAMLBuilder) Map(key string) *YAMLBuilder {
+	y.openKey(false)
+	y.key, y.hasKey = key, true
+	return y
+}
+
+// List opens a sequence under the pending key. Items are
+// added with Value or Item and the sequence is closed
+// with End.
+func (y *YAMLBuilder) List() *YAMLBuilder {
+	if !y.hasKey {
+		misuse("YAMLBuilder.List: no pending key")
+		return y
+	}
+	y.openKey(true)
+	return y
+}
+
+// Item opens a mapping as the next item of the current
+// list. The first key of the mapping is written after
+// the "- " marker. Close the item with End.
+func (y *YAMLBuilder) Item() *YAMLBuilder {
+	if !y.inList() {
+		misuse("YAMLBuilder.Item: not in a list")
+		return y
+	}
+	y.stack = append(y.stack, yamlLevel{dash: true})
+	return y
+}
+
+// Value writes a scalar: as the value of the pending key,
+// or as the next item of the current list.
+func (y *YAMLBuilder) Value(v string) *YAMLBuilder {
+	switch {
+	case y.hasKey:
+		y.indent()
+		writeYAMLScalar(y.sb, y.key)
+		y.sb.WriteString(": ")
+		writeYAMLScalar(y.sb, v)
+		y.sb.WriteByte('\n')
+		y.hasKey = false
+	case y.inList():
+		y.indent()
+		y.sb.WriteString("- ")
+		writeYAMLScalar(y.sb, v)
+		y.sb.WriteByte('\n')
+	default:
+		misuse("YAMLBuilder.Value: no pending key")
+	}
+	return y
+}
+
+// End closes the innermost open mapping, list or item. A
+// pending key without a value is written as an empty
+// (null) value.
+func (y *YAMLBuilder) End() *YAMLBuilder {
+	y.flushKey()
+	if len(y.stack) > 0 {
+		y.stack = y.stack[:len(y.stack)-1]
+	}
+	return y
+}
+
+// String returns the YAML document and releases the
+// underlying builder back to the pool. Later calls return
+// the same document.
+func (y *YAMLBuilder) String() string {
+	if y.isReleased {
+		return y.result
+	}
+	y.flushKey()
+	y.result = y.sb.String()
+	Release(y.sb)
+	y.sb = nil
+	y.isReleased = true
+	return y.result
+}
+
+// inList reports whether the innermost block is a list.
+func (y *YAMLBuilder) inList() bool {
+	return len(y.stack) > 0 && y.stack[len(y.stack)-1].list
+}
+
+// openKey writes the pending key, if any, as the parent of
+// a nested list or mapping block.
+func (y *YAMLBuilder) openKey(list bool) {
+	if !y.hasKey {
+		return
+	}
+	y.indent()
+	writeYAMLScalar(y.sb, y.key)
+	y.sb.WriteString(":\n")
+	y.hasKey = false
+	y.stack = append(y.stack, yamlLevel{list: list})
+}
+
+// flushKey writes a pending key that never received a value.
+func (y *YAMLBuilder) flushKey() {
+	if !y.hasKey {
+		return
+	}
+	y.indent()
+	writeYAMLScalar(y.sb, y.key)
+	y.sb.WriteString(":\n")
+	y.hasKey = false
+}
+
+// indent writes the indentation for a line at the current
+// depth, replacing the last two spaces with "- " on the
+// first line of a list item.
+func (y *YAMLBuilder) indent() {
+	n := yamlIndent * len(y.stack)
+	dash := n > 0 && y.stack[len(y.stack)-1].dash
+	if dash {
+		y.stack[len(y.stack)-1].dash = false
+		n -= yamlIndent
+	}
+	for i := 0; i < n; i++ {
+		y.sb.WriteByte(' ')
+	}
+	if dash {
+		y.sb.WriteString("- ")
+	}
+}
+
+// writeYAMLScalar writes s as a plain scalar, or as a double
+// quoted scalar if a plain one would be misread.
+func writeYAMLScalar(sb *strings.Builder, s string) {
+	if yamlNeedsQuote(s) {
+		writeYAMLString(sb, s)
+		return
+	}
+	sb.WriteString(s)
+}
+
+// writeYAMLString writes s into sb as a double quoted YAML
+// scalar. Quotes, backslashes and the non-printable control
+// characters, including DEL and the C1 controls, are
+// escaped. Invalid UTF-8 is replaced with U+FFFD.
+func writeYAMLString(sb *strings.Builder, s string) {
+	sb.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != 0x7f && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			sb.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				sb.WriteByte('\\')
+				sb.WriteByte(c)
+			case '\n':
+				sb.WriteString(`\n`)
+			case '\r':
+				sb.WriteString(`\r`)
+			case '\t':
+				sb.WriteString(`\t`)
+			default:
+				writeYAMLHex(sb, c)
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			sb.WriteString(s[start:i])
+			sb.WriteString(`\uFFFD`)
+		case r >= 0x80 && r <= 0x9f:
+			sb.WriteString(s[start:i])
+			writeYAMLHex(sb, byte(r))
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	sb.WriteString(s[start:])
+	sb.WriteByte('"')
+}
+
+// writeYAMLHex writes the character c as a \x escape.
+func writeYAMLHex(sb *strings.Builder, c byte) {
+	sb.WriteString(`\x`)
+	sb.WriteByte(hexDigits[c>>4])
+	sb.WriteByte(hexDigits[c&0xF])
+}
+
+// yamlNeedsQuote reports whether s cannot be written as a
+// plain YAML scalar.
+func yamlNeedsQuote(s string) bool {
+	if s == "" || s[0] == ' ' || s[len(s)-1] == ' ' {
+		return true
+	}
+	if strings.IndexByte("-?:,[]{}#&*!|>'\"%@`", s[0]) >= 0 {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || s[len(s)-1] == ':' {
+		return true
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] == 0x7f {
+			return true
+		}
+		// C1 control characters, U+0080 to U+009F
+		if s[i] == 0xc2 && i+1 < len(s) && s[i+1] <= 0x9f {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2021 Michael Treanor
+// https://github.com/skeptycal
+// MIT License
+
+package stringpool
+
+import "testing"
+
+func TestYAMLBuilder(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(y *YAMLBuilder)
+		want  string
+	}{
+		{"empty", func(y *YAMLBuilder) {}, ""},
+		{"scalars", func(y *YAMLBuilder) {
+			y.Map("name").Value("app")
+			y.Map("port").Value("8080")
+		}, "name: app\nport: 8080\n"},
+		{"nested maps", func(y *YAMLBuilder) {
+			y.Map("server")
+			y.Map("host").Value("localhost")
+			y.Map("tls")
+			y.Map("enabled").Value("true")
+			y.End()
+			y.Map("port").Value("443")
+			y.End()
+			y.Map("debug").Value("false")
+		}, "server:\n  host: localhost\n  tls:\n    enabled: true\n  port: 443\ndebug: false\n"},
+		{"list", func(y *YAMLBuilder) {
+			y.Map("tags").List()
+			y.Value("web")
+			y.Value("api")
+			y.End()
+		}, "tags:\n  - web\n  - api\n"},
+		{"list of maps", func(y *YAMLBuilder) {
+			y.Map("users").List()
+			y.Item()
+			y.Map("name").Value("alice")
+			y.Map("admin").Value("true")
+			y.End()
+			y.Item()
+			y.Map("name").Value("bob")
+			y.Map("groups").List()
+			y.Value("dev")
+			y.End()
+			y.End()
+			y.End()
+		}, "users:\n  - name: alice\n    admin: true\n  - name: bob\n    groups:\n      - dev\n"},
+		{"quoted", func(y *YAMLBuilder) {
+			y.Map("msg").Value("a: b")
+			y.Map("empty").Value("")
+			y.Map("#key").Value("-dash")
+		}, "msg: \"a: b\"\nempty: \"\"\n\"#key\": \"-dash\"\n"},
+		{"non-printable", func(y *YAMLBuilder) {
+			y.Map("del").Value("a\x7fb")
+			y.Map("tab").Value("a\tb")
+			y.Map("c1").Value("a\u0085b")
+		}, `del: "a\x7fb"` + "\n" + `tab: "a\tb"` + "\n" + `c1: "a\x85b"` + "\n"},
+		{"null value", func(y *YAMLBuilder) {
+			y.Map("a")
+			y.End()
+			y.Map("b")
+		}, "a:\nb:\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			y := NewYAMLBuilder()
+			tt.build(y)
+			if got := y.String(); got != tt.want {
+				t.Errorf("YAMLBuilder.String() = %q, want %q", got, tt.want)
+			}
+			if got := y.String(); got != tt.want {
+				t.Errorf("YAMLBuilder.String() second call = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}